@@ -0,0 +1,224 @@
+package census
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Apgcodes: codes of the objects used in the catalogues of objects, like Catagolue.
+// The code has a prefix with the type of object, see `getCodePrefix`, and the points of the
+// object in the extended Wechsler format, separated by "_". Example: "xq4_153" is the glider.
+//
+// In the extended Wechsler format the object is split in strips of 5 rows, separated by "z".
+// Each column of a strip is a character of `wechslerDigits`, where the point of the row N is
+// the bit N. The disabled columns at the end of the strips are removed and the sequences of
+// disabled columns are shortened: "w" are 2 columns, "x" 3 columns and "y" and a character
+// of `wechslerZeros` are from 4 to 39 columns.
+
+// Characters of the columns of the strips.
+const wechslerDigits string = "0123456789abcdefghijklmnopqrstuv"
+
+// Characters after "y" with the number of disabled columns minus 4.
+const wechslerZeros string = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// Rows of a strip.
+const wechslerStripHeight int = 5
+
+// Returns the disabled columns `zeros` in the extended Wechsler format.
+func encodeWechslerZeros(zeros int) string {
+	max := len(wechslerZeros) + 3
+	text := ""
+
+	for ; zeros > max; zeros -= max {
+		text += "y" + wechslerZeros[len(wechslerZeros)-1:]
+	}
+
+	switch zeros {
+	case 0:
+		return text
+	case 1:
+		return text + "0"
+	case 2:
+		return text + "w"
+	case 3:
+		return text + "x"
+	default:
+		return text + "y" + string(wechslerZeros[zeros-4])
+	}
+}
+
+// Returns the points `positions` in the extended Wechsler format.
+// The points have to be normalized, see `normalize`.
+func encodeWechsler(positions []game.Position) string {
+	width, height := 0, 0
+	columns := map[game.Position]int{}
+
+	for _, p := range positions {
+		strip := p[1] / wechslerStripHeight
+		columns[game.Position{p[0], strip}] |= 1 << uint(p[1]%wechslerStripHeight)
+
+		if p[0] >= width {
+			width = p[0] + 1
+		}
+
+		if p[1] >= height {
+			height = p[1] + 1
+		}
+	}
+
+	var b strings.Builder
+	strips := (height + wechslerStripHeight - 1) / wechslerStripHeight
+
+	for strip := 0; strip < strips; strip++ {
+		if strip > 0 {
+			b.WriteString("z")
+		}
+
+		zeros := 0
+		for x := 0; x < width; x++ {
+			column := columns[game.Position{x, strip}]
+			if column == 0 {
+				zeros++
+				continue
+			}
+
+			b.WriteString(encodeWechslerZeros(zeros))
+			b.WriteByte(wechslerDigits[column])
+			zeros = 0
+		}
+	}
+
+	return b.String()
+}
+
+// Returns the points of the object in the extended Wechsler format `text`, sorted by rows.
+// Returns an error whether the format is invalid.
+func decodeWechsler(text string) ([]game.Position, error) {
+	positions := []game.Position{}
+	x, strip := 0, 0
+
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == 'z':
+			x, strip = 0, strip+1
+		case c == 'w':
+			x += 2
+		case c == 'x':
+			x += 3
+		case c == 'y':
+			if i+1 >= len(text) || strings.IndexByte(wechslerZeros, text[i+1]) < 0 {
+				return nil, InvalidApgcodeError(text, "the character \"y\" needs the number of columns")
+			}
+
+			i++
+			x += strings.IndexByte(wechslerZeros, text[i]) + 4
+		default:
+			column := strings.IndexByte(wechslerDigits, c)
+			if column < 0 {
+				return nil, InvalidApgcodeError(text, "the character \""+string(c)+"\" is unknown")
+			}
+
+			for row := 0; row < wechslerStripHeight; row++ {
+				if column&(1<<uint(row)) != 0 {
+					positions = append(positions, game.Position{x, strip*wechslerStripHeight + row})
+				}
+			}
+
+			x++
+		}
+	}
+
+	return normalize(positions), nil
+}
+
+// Returns whether the code `a` goes before the code `b`: the shortest code, or the first in
+// alphabetical order when they have the same length.
+func isSmallerCode(a, b string) bool {
+	return len(a) < len(b) || (len(a) == len(b) && a < b)
+}
+
+// Returns the apgcode of the object with the points `positions`, run with the rule `rule`.
+// The code is the smallest of all the phases, rotations and reflections of the object.
+// Returns an error whether the object has not period in `MAX_CYCLES` cycles.
+func Encode(positions []game.Position, rule *game.Rule) (string, error) {
+	o, err := analyseObject(positions, rule)
+	if err != nil {
+		return "", err
+	}
+
+	if o.Code == UNSTABLE {
+		return "", UnstableObjectError(MAX_CYCLES)
+	}
+
+	return o.Code, nil
+}
+
+// Same as `Encode` but the object is the points enabled of the universe `m`.
+func EncodeUniverse(m matrix.Universe, rule *game.Rule) (string, error) {
+	positions := []game.Position{}
+	m.ForEachEnabled(func(x, y int) {
+		positions = append(positions, game.Position{x, y})
+	})
+
+	return Encode(positions, rule)
+}
+
+// Returns the points of the apgcode `code`, moved to the position 0, 0 and sorted by rows.
+// Returns an error whether the code is invalid.
+func Decode(code string) ([]game.Position, error) {
+	parts := strings.SplitN(code, "_", 2)
+	if len(parts) != 2 || len(parts[0]) < 3 {
+		return nil, InvalidApgcodeError(code, "the code needs a prefix and the points")
+	}
+
+	kind := parts[0][:2]
+	if kind != "xs" && kind != "xp" && kind != "xq" {
+		return nil, InvalidApgcodeError(code, "the prefix \""+kind+"\" is unknown")
+	}
+
+	n, err := strconv.Atoi(parts[0][2:])
+	if err != nil || n < 1 {
+		return nil, InvalidApgcodeError(code, "the number of the prefix is invalid")
+	}
+
+	positions, err := decodeWechsler(parts[1])
+	if err != nil {
+		return nil, InvalidApgcodeError(code, "the points are invalid")
+	}
+
+	if len(positions) == 0 {
+		return nil, InvalidApgcodeError(code, "the object has not points")
+	}
+
+	if kind == "xs" && n != len(positions) {
+		return nil, InvalidApgcodeError(code, "the population of the prefix is wrong")
+	}
+
+	return positions, nil
+}
+
+// Makes a new matrix of size `width`x`height`, with the boundary `boundary`, and the points
+// of the apgcode `code` in its top-left corner.
+// Returns an error whether the code is invalid or the object does not fit in the matrix.
+func DecodeMatrix(code string, width, height int, boundary matrix.Boundary) (*matrix.Matrix, error) {
+	positions, err := Decode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := matrix.NewWithBoundary(width, height, boundary)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range positions {
+		if err := m.EnablePoint(p[0], p[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
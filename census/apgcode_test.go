@@ -0,0 +1,102 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the functions encodeWechsler and decodeWechsler.
+func TestWechsler(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[string][]game.Position{
+		// Glider.
+		"153": {{0, 0}, {1, 0}, {2, 0}, {2, 1}, {1, 2}},
+		// Points in two strips, the second strip is shorter.
+		"11z1": {{0, 0}, {1, 0}, {0, 5}},
+		// Disabled columns.
+		"101w1x1y01y51": {{0, 0}, {2, 0}, {5, 0}, {9, 0}, {14, 0}, {24, 0}},
+		// Empty strip.
+		"1zz1": {{0, 0}, {0, 10}},
+	}
+
+	for code, positions := range tests {
+		assert.Equal(encodeWechsler(positions), code, "Invalid encoded points.")
+
+		decoded, err := decodeWechsler(code)
+		assert.Equal(err, nil, "There is an error decoding the points.")
+		assert.Equal(decoded, normalize(positions), "Invalid decoded points.")
+	}
+
+	// More than 39 disabled columns.
+	positions := []game.Position{{0, 0}, {41, 0}}
+	assert.Equal(encodeWechsler(positions), "1yz01", "Invalid long sequence of disabled columns.")
+	decoded, _ := decodeWechsler("1yz01")
+	assert.Equal(decoded, positions, "Invalid decoded long sequence of disabled columns.")
+
+	for _, code := range []string{"1y", "1-", "A"} {
+		_, err := decodeWechsler(code)
+		assert.NotEqual(err, nil, "There is not error with an invalid code.")
+	}
+}
+
+// Test the function Encode.
+func TestEncode(t *testing.T) {
+	assert := assert.New(t)
+	rule := game.MustParseRule(game.CONWAY_RULE)
+
+	// Vertical blinker in other position.
+	code, err := Encode([]game.Position{{5, 5}, {5, 6}, {5, 7}}, rule)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(code, "xp2_7", "Invalid code of the blinker.")
+
+	// The pre-block becomes a block.
+	code, _ = Encode([]game.Position{{0, 0}, {1, 0}, {0, 1}}, rule)
+	assert.Equal(code, "xs4_33", "Invalid code of the pre-block.")
+
+	// Pentadecathlon.
+	m := matrix.NewSparse()
+	for _, p := range []game.Position{{1, 0}, {1, 1}, {0, 2}, {2, 2}, {1, 3}, {1, 4}, {1, 5}, {1, 6}, {0, 7}, {2, 7}, {1, 8}, {1, 9}} {
+		m.EnablePoint(p[0], p[1])
+	}
+
+	code, err = EncodeUniverse(m, rule)
+	assert.Equal(err, nil, "There is an error encoding the universe.")
+	assert.Equal(code, "xp15_4r4z4r4", "Invalid code of the pentadecathlon.")
+
+	// R-pentomino.
+	_, err = Encode([]game.Position{{1, 0}, {2, 0}, {0, 1}, {1, 1}, {1, 2}}, rule)
+	assert.Equal(err, UnstableObjectError(MAX_CYCLES), "Invalid error of the unstable object.")
+}
+
+// Test the functions Decode and DecodeMatrix.
+func TestDecode(t *testing.T) {
+	assert := assert.New(t)
+
+	positions, err := Decode("xq4_6frc")
+	assert.Equal(err, nil, "There is an error.")
+	// The phase of the code has 12 points.
+	assert.Equal(len(positions), 12, "Invalid population of the lightweight spaceship.")
+
+	for _, code := range []string{"xs4", "xs_33", "xa4_33", "xs0_33", "xs5_33", "xp2_", "xp2_1y"} {
+		_, err := Decode(code)
+		assert.NotEqual(err, nil, "There is not error with the code "+code+".")
+	}
+
+	m, err := DecodeMatrix("xs4_33", 10, 10, matrix.BOUNDARY_DEAD)
+	assert.Equal(err, nil, "There is an error decoding the matrix.")
+	assert.Equal(m.GetPointsEnabled(), 4, "Invalid points of the matrix.")
+
+	_, err = DecodeMatrix("xs4_33", 1, 1, matrix.BOUNDARY_DEAD)
+	assert.NotEqual(err, nil, "There is not error with a small matrix.")
+
+	// The codes of the table run to the same code.
+	rule := game.MustParseRule(game.CONWAY_RULE)
+	for _, e := range tableEntries {
+		positions, _ := Decode(e.code)
+		code, _ := Encode(positions, rule)
+		assert.Equal(code, e.code, "Invalid code of the "+e.name+".")
+	}
+}
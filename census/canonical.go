@@ -0,0 +1,69 @@
+package census
+
+import (
+	"sort"
+
+	"github.com/davidnotplay/gameoflife/game"
+)
+
+// The 8 symmetries of the square: the rotations and the reflections of the points.
+var symmetries [8]func(p game.Position) game.Position = [8]func(p game.Position) game.Position{
+	func(p game.Position) game.Position { return game.Position{p[0], p[1]} },
+	func(p game.Position) game.Position { return game.Position{-p[1], p[0]} },
+	func(p game.Position) game.Position { return game.Position{-p[0], -p[1]} },
+	func(p game.Position) game.Position { return game.Position{p[1], -p[0]} },
+	func(p game.Position) game.Position { return game.Position{-p[0], p[1]} },
+	func(p game.Position) game.Position { return game.Position{p[0], -p[1]} },
+	func(p game.Position) game.Position { return game.Position{p[1], p[0]} },
+	func(p game.Position) game.Position { return game.Position{-p[1], -p[0]} },
+}
+
+// Returns the points `positions` moved to the position 0, 0 and sorted by rows.
+func normalize(positions []game.Position) []game.Position {
+	if len(positions) == 0 {
+		return []game.Position{}
+	}
+
+	minX, minY := positions[0][0], positions[0][1]
+	for _, p := range positions {
+		if p[0] < minX {
+			minX = p[0]
+		}
+
+		if p[1] < minY {
+			minY = p[1]
+		}
+	}
+
+	normalized := make([]game.Position, len(positions))
+	for i, p := range positions {
+		normalized[i] = game.Position{p[0] - minX, p[1] - minY}
+	}
+
+	sort.Slice(normalized, func(i, j int) bool {
+		a, b := normalized[i], normalized[j]
+		return a[1] < b[1] || (a[1] == b[1] && a[0] < b[0])
+	})
+
+	return normalized
+}
+
+// Returns the canonical points of the points `positions` in the extended Wechsler format: the
+// smallest code of its symmetries, so the points rotated or reflected have the same code.
+func getCanonicalWechsler(positions []game.Position) string {
+	canonical := ""
+
+	for i, symmetry := range symmetries {
+		transformed := make([]game.Position, len(positions))
+		for j, p := range positions {
+			transformed[j] = symmetry(p)
+		}
+
+		code := encodeWechsler(normalize(transformed))
+		if i == 0 || isSmallerCode(code, canonical) {
+			canonical = code
+		}
+	}
+
+	return canonical
+}
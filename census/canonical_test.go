@@ -0,0 +1,39 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function normalize.
+func TestNormalize(t *testing.T) {
+	assert := assert.New(t)
+
+	normalized := normalize([]game.Position{{5, -2}, {3, -1}, {4, -2}})
+	assert.Equal(normalized, []game.Position{{1, 0}, {2, 0}, {0, 1}}, "Invalid positions.")
+	assert.Equal(normalize([]game.Position{}), []game.Position{}, "Invalid empty positions.")
+}
+
+// Test the function getCanonicalWechsler.
+func TestGetCanonicalWechsler(t *testing.T) {
+	assert := assert.New(t)
+	glider := []game.Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	code := getCanonicalWechsler(glider)
+
+	// The same code in all the rotations, reflections and positions.
+	for _, symmetry := range symmetries {
+		transformed := []game.Position{}
+		for _, p := range glider {
+			q := symmetry(p)
+			transformed = append(transformed, game.Position{q[0] + 100, q[1] - 7})
+		}
+
+		assert.Equal(getCanonicalWechsler(transformed), code, "Invalid code of the transformed glider.")
+	}
+
+	// The vertical domino is shorter than the horizontal domino.
+	assert.Equal(getCanonicalWechsler([]game.Position{{0, 0}, {1, 0}}), "3", "Invalid canonical code.")
+	assert.NotEqual(getCanonicalWechsler(glider[:4]), code, "The code of other object is the same.")
+}
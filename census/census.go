@@ -0,0 +1,237 @@
+package census
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Distance by default between the points of the same object. With distance 2, the points
+// separated by one point disabled are in the same object.
+const DEFAULT_DISTANCE int = 2
+
+// Maximum number of cycles run to find the period of an object.
+const MAX_CYCLES uint = 1000
+
+// Name of the objects without period in `MAX_CYCLES` cycles.
+const UNSTABLE string = "unstable"
+
+// Group of points enabled near to each other.
+type Object struct {
+	// Points of the object.
+	Positions []game.Position
+
+	// Bounding box of the object.
+	Box matrix.Rect
+
+	// Period of the object. Zero when it is not found.
+	Period uint
+
+	// Displacement of the object in each period. Zero when it is not a spaceship.
+	Dx, Dy int
+
+	// Whether the object repeats its current state. When it is false, the object changes
+	// before becoming periodic, or it is not periodic.
+	Stable bool
+
+	// Name of the object in the table of common objects. Empty when it is not found.
+	Name string
+
+	// Apgcode of the object: the same in all its phases, rotations and reflections.
+	// Example: "xs4_33". `UNSTABLE` when the period is not found.
+	Code string
+}
+
+// Returns the label of the object: its name, or its code when it has not name.
+func (self *Object) GetLabel() string {
+	if self.Name != "" {
+		return self.Name
+	}
+
+	return self.Code
+}
+
+// Returns the points enabled of the universe `m` split in objects. Two points are in the
+// same object when their horizontal and vertical distances are `distance` or less.
+// The objects are sorted by the position of their top-left point.
+func FindComponents(m matrix.Universe, distance int) [][]game.Position {
+	points := []game.Position{}
+	enabled := map[game.Position]bool{}
+
+	m.ForEachEnabled(func(x, y int) {
+		p := game.Position{x, y}
+		points = append(points, p)
+		enabled[p] = true
+	})
+
+	sort.Slice(points, func(i, j int) bool {
+		a, b := points[i], points[j]
+		return a[1] < b[1] || (a[1] == b[1] && a[0] < b[0])
+	})
+
+	visited := map[game.Position]bool{}
+	components := [][]game.Position{}
+
+	for _, start := range points {
+		if visited[start] {
+			continue
+		}
+
+		visited[start] = true
+		component := []game.Position{start}
+
+		for i := 0; i < len(component); i++ {
+			p := component[i]
+			for dx := -distance; dx <= distance; dx++ {
+				for dy := -distance; dy <= distance; dy++ {
+					q := game.Position{p[0] + dx, p[1] + dy}
+					if enabled[q] && !visited[q] {
+						visited[q] = true
+						component = append(component, q)
+					}
+				}
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	return components
+}
+
+// Returns the code prefix of an object with population `population`, period `period` and
+// displacement `dx`, `dy`: "xs" and the population for the still lifes, "xp" and the period
+// for the oscillators and "xq" and the period for the spaceships.
+func getCodePrefix(population int, period uint, dx, dy int) string {
+	switch {
+	case dx != 0 || dy != 0:
+		return fmt.Sprintf("xq%d", period)
+	case period > 1:
+		return fmt.Sprintf("xp%d", period)
+	default:
+		return fmt.Sprintf("xs%d", population)
+	}
+}
+
+// Runs the object with the points `positions` alone, using the rule `rule`, to find its
+// period and its apgcode. Returns the error of the game.
+func analyseObject(positions []game.Position, rule *game.Rule) (*Object, error) {
+	o := &Object{Positions: positions, Code: UNSTABLE}
+	for i, p := range positions {
+		if i == 0 {
+			o.Box = matrix.Rect{MinX: p[0], MinY: p[1], MaxX: p[0], MaxY: p[1]}
+		}
+
+		o.Box = o.Box.Extend(p[0], p[1])
+	}
+
+	options := game.Options{Rule: rule, Storage: matrix.STORAGE_SPARSE, StopOnPeriod: true}
+	g, err := game.NewWithOptions(0, 0, positions, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := g.Run(MAX_CYCLES); err != nil {
+		return nil, err
+	}
+
+	period, start, found := g.GetPeriod()
+	if !found {
+		return o, nil
+	}
+
+	o.Period, o.Stable = period, start == 0
+	o.Dx, o.Dy, _ = g.GetDisplacement()
+
+	// The code is the smallest code of the phases.
+	canonical := ""
+	for i := uint(0); i < period; i++ {
+		phase := []game.Position{}
+		g.GetMatrix().ForEachEnabled(func(x, y int) {
+			phase = append(phase, game.Position{x, y})
+		})
+
+		code := getCanonicalWechsler(phase)
+		if i == 0 || isSmallerCode(code, canonical) {
+			canonical = code
+		}
+
+		if err := g.Cycle(); err != nil {
+			return nil, err
+		}
+	}
+
+	o.Code = getCodePrefix(g.GetMatrix().GetPointsEnabled(), period, o.Dx, o.Dy) + "_" + canonical
+	return o, nil
+}
+
+// Splits the points enabled of the universe `m` in objects, see `FindComponents`, and
+// classifies them running each one alone with the rule `rule`.
+// The objects are named using the table of common objects in the Conway's rule.
+// Returns an error whether the rule has B0, because the objects can not be run alone, or
+// whether it is a Generations rule.
+func Analyse(m matrix.Universe, rule *game.Rule, distance int) ([]*Object, error) {
+	objects := []*Object{}
+	conway := rule.String() == game.CONWAY_RULE
+
+	for _, positions := range FindComponents(m, distance) {
+		o, err := analyseObject(positions, rule)
+		if err != nil {
+			return nil, err
+		}
+
+		if e, ok := table[o.Code]; ok && conway {
+			o.Name = e.name
+		}
+
+		objects = append(objects, o)
+	}
+
+	return objects, nil
+}
+
+// Number of objects by label.
+type Census map[string]int
+
+// Makes the census of the objects `objects`.
+func NewCensus(objects []*Object) Census {
+	c := Census{}
+	for _, o := range objects {
+		c[o.GetLabel()]++
+	}
+
+	return c
+}
+
+// Returns the labels of the census, from the most common to the least common.
+func (self Census) GetLabels() []string {
+	labels := []string{}
+	for label := range self {
+		labels = append(labels, label)
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		a, b := labels[i], labels[j]
+		return self[a] > self[b] || (self[a] == self[b] && a < b)
+	})
+
+	return labels
+}
+
+// Returns the census as text. Example: "12 blocks, 4 blinkers, 2 gliders".
+func (self Census) String() string {
+	parts := []string{}
+	for _, label := range self.GetLabels() {
+		n := self[label]
+		if n != 1 {
+			label = getPlural(label)
+		}
+
+		parts = append(parts, fmt.Sprintf("%d %s", n, label))
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,135 @@
+package census
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a sparse universe with the objects `objects` moved to the positions `positions`.
+func auxUniverse(objects [][]game.Position, positions []game.Position) matrix.Universe {
+	m := matrix.NewSparse()
+	for i, o := range objects {
+		for _, p := range o {
+			m.EnablePoint(p[0]+positions[i][0], p[1]+positions[i][1])
+		}
+	}
+
+	return m
+}
+
+// Returns the points of the object `name` of the table.
+func auxObject(name string) []game.Position {
+	for _, e := range tableEntries {
+		if e.name == name {
+			positions, _ := Decode(e.code)
+			return positions
+		}
+	}
+
+	panic("object not found")
+}
+
+// Test the function FindComponents.
+func TestFindComponents(t *testing.T) {
+	assert := assert.New(t)
+	block, blinker := auxObject("block"), auxObject("blinker")
+
+	// The blinker is 1 point at the right of the block, and the other block is far away.
+	m := auxUniverse([][]game.Position{block, blinker, block}, []game.Position{{0, 0}, {3, 0}, {20, 20}})
+
+	components := FindComponents(m, DEFAULT_DISTANCE)
+	assert.Equal(len(components), 2, "Invalid number of components with the default distance.")
+	assert.Equal(len(components[0]), 7, "Invalid size of the first component.")
+	assert.Equal(len(components[1]), 4, "Invalid size of the second component.")
+
+	components = FindComponents(m, 1)
+	assert.Equal(len(components), 3, "Invalid number of components with the distance 1.")
+	assert.Equal(components[2][0], game.Position{20, 20}, "The components are not sorted.")
+
+	assert.Equal(len(FindComponents(matrix.NewSparse(), 1)), 0, "There are components in an empty universe.")
+}
+
+// Test the objects of the table are classified in all the phases and orientations.
+func TestAnalyseTable(t *testing.T) {
+	assert := assert.New(t)
+	rule := game.MustParseRule(game.CONWAY_RULE)
+
+	for _, e := range tableEntries {
+		// Rotated object after 3 cycles.
+		rotated := []game.Position{}
+		for _, p := range auxObject(e.name) {
+			rotated = append(rotated, symmetries[1](p))
+		}
+
+		g, _ := game.NewWithOptions(0, 0, rotated, game.Options{Storage: matrix.STORAGE_SPARSE})
+		g.Run(3)
+
+		objects, err := Analyse(g.GetMatrix(), rule, DEFAULT_DISTANCE)
+		assert.Equal(err, nil, "There is an error.")
+		assert.Equal(len(objects), 1, fmt.Sprintf("Invalid number of objects of the %s.", e.name))
+		assert.Equal(objects[0].Name, e.name, fmt.Sprintf("Invalid name of the %s.", e.name))
+		assert.Equal(objects[0].Code, e.code, fmt.Sprintf("Invalid code of the %s.", e.name))
+		assert.Equal(objects[0].Stable, true, fmt.Sprintf("The %s is not stable.", e.name))
+	}
+}
+
+// Test the function Analyse.
+func TestAnalyse(t *testing.T) {
+	assert := assert.New(t)
+	rule := game.MustParseRule(game.CONWAY_RULE)
+	glider := auxObject("glider")
+
+	// Block, glider, unknown still life (bi-block), and R-pentomino.
+	biblock := []game.Position{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {3, 0}, {4, 0}, {3, 1}, {4, 1}}
+	rpentomino := []game.Position{{1, 0}, {2, 0}, {0, 1}, {1, 1}, {1, 2}}
+	m := auxUniverse([][]game.Position{auxObject("block"), glider, biblock, rpentomino},
+		[]game.Position{{0, 0}, {10, 0}, {20, 0}, {40, 0}})
+
+	objects, err := Analyse(m, rule, DEFAULT_DISTANCE)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(len(objects), 4, "Invalid number of objects.")
+
+	assert.Equal(objects[0].Name, "block", "Invalid name of the block.")
+	assert.Equal(objects[0].Box, matrix.Rect{MinX: 0, MinY: 0, MaxX: 1, MaxY: 1}, "Invalid box of the block.")
+	assert.Equal(objects[0].Period, uint(1), "Invalid period of the block.")
+
+	assert.Equal(objects[1].Name, "glider", "Invalid name of the glider.")
+	assert.Equal([3]int{int(objects[1].Period), objects[1].Dx, objects[1].Dy}, [3]int{4, 1, -1}, "Invalid movement of the glider.")
+
+	assert.Equal(objects[2].Name, "", "The bi-block has name.")
+	assert.Equal(objects[2].Code, "xs8_rr", "Invalid code of the bi-block.")
+	assert.Equal(objects[2].GetLabel(), "xs8_rr", "Invalid label of the bi-block.")
+
+	assert.Equal(objects[3].Code, UNSTABLE, "The R-pentomino is not unstable.")
+	assert.Equal(objects[3].Stable, false, "The R-pentomino is stable.")
+	assert.Equal(objects[3].Period, uint(0), "The R-pentomino has period.")
+
+	// Other rules have not names.
+	objects, _ = Analyse(auxUniverse([][]game.Position{auxObject("block")}, []game.Position{{0, 0}}), game.MustParseRule("B36/S23"), 1)
+	assert.Equal(objects[0].Name, "", "The object has name in other rule.")
+	assert.Equal(objects[0].Code, "xs4_33", "Invalid code in other rule.")
+
+	_, err = Analyse(m, game.MustParseRule("B03/S23"), 1)
+	assert.NotEqual(err, nil, "There is not error with a B0 rule.")
+}
+
+// Test the census of a settled soup.
+func TestCensus(t *testing.T) {
+	assert := assert.New(t)
+	block, blinker, glider := auxObject("block"), auxObject("blinker"), auxObject("glider")
+	m := auxUniverse(
+		[][]game.Position{block, block, blinker, glider, block, auxObject("loaf"), auxObject("loaf")},
+		[]game.Position{{0, 0}, {10, 0}, {20, 0}, {30, 0}, {0, 10}, {10, 10}, {20, 10}})
+
+	objects, _ := Analyse(m, game.MustParseRule(game.CONWAY_RULE), DEFAULT_DISTANCE)
+	census := NewCensus(objects)
+
+	assert.Equal(census, Census{"block": 3, "loaf": 2, "blinker": 1, "glider": 1}, "Invalid census.")
+	assert.Equal(census.GetLabels(), []string{"block", "loaf", "blinker", "glider"}, "Invalid labels order.")
+	assert.Equal(census.String(), "3 blocks, 2 loaves, 1 blinker, 1 glider", "Invalid census text.")
+	assert.Equal(Census{"xs8_rr": 2}.String(), "2 xs8_rr", "Invalid census text of the unknown objects.")
+}
@@ -0,0 +1,30 @@
+package census
+
+import (
+	"fmt"
+)
+
+type invalidApgcodeError struct {
+	code   string
+	reason string
+}
+
+func (e *invalidApgcodeError) Error() string {
+	message := "The apgcode \"%s\" is invalid: %s."
+	return fmt.Sprintf(message, e.code, e.reason)
+}
+
+func InvalidApgcodeError(code, reason string) error {
+	return &invalidApgcodeError{code, reason}
+}
+
+type unstableObjectError uint
+
+func (e *unstableObjectError) Error() string {
+	return fmt.Sprintf("The object has not period in %d cycles.", uint(*e))
+}
+
+func UnstableObjectError(cycles uint) error {
+	err := unstableObjectError(cycles)
+	return &err
+}
@@ -0,0 +1,70 @@
+package census
+
+import (
+	"github.com/davidnotplay/gameoflife/game"
+)
+
+// Common object of the Conway's game of life.
+type tableEntry struct {
+	name   string
+	plural string
+
+	// Apgcode of the object, used in the catalogues of objects.
+	code string
+}
+
+// Common objects of the Conway's game of life, from the most common to the least common.
+var tableEntries []tableEntry = []tableEntry{
+	{"block", "blocks", "xs4_33"},
+	{"blinker", "blinkers", "xp2_7"},
+	{"beehive", "beehives", "xs6_356"},
+	{"loaf", "loaves", "xs7_2596"},
+	{"boat", "boats", "xs5_253"},
+	{"glider", "gliders", "xq4_153"},
+	{"tub", "tubs", "xs4_252"},
+	{"pond", "ponds", "xs8_6996"},
+	{"ship", "ships", "xs6_696"},
+	{"long boat", "long boats", "xs7_25ac"},
+	{"toad", "toads", "xp2_7e"},
+	{"beacon", "beacons", "xp2_318c"},
+	{"lightweight spaceship", "lightweight spaceships", "xq4_6frc"},
+	{"middleweight spaceship", "middleweight spaceships", "xq4_27dee6"},
+	{"heavyweight spaceship", "heavyweight spaceships", "xq4_27deee6"},
+}
+
+// Common objects by apgcode.
+var table map[string]*tableEntry = buildTable()
+
+// Returns the table of the common objects by apgcode.
+// The codes are checked running the objects, so a wrong code is found on start.
+func buildTable() map[string]*tableEntry {
+	t := map[string]*tableEntry{}
+	rule := game.MustParseRule(game.CONWAY_RULE)
+
+	for i := range tableEntries {
+		e := &tableEntries[i]
+		positions, err := Decode(e.code)
+		if err != nil {
+			panic(err)
+		}
+
+		if code, err := Encode(positions, rule); err != nil || code != e.code {
+			panic("the apgcode of the object " + e.name + " of the census table is wrong")
+		}
+
+		t[e.code] = e
+	}
+
+	return t
+}
+
+// Returns the plural of the object name `name`. The names not found are not changed.
+func getPlural(name string) string {
+	for _, e := range tableEntries {
+		if e.name == name {
+			return e.plural
+		}
+	}
+
+	return name
+}
@@ -0,0 +1,385 @@
+// Command gameoflife runs a pattern file a number of generations and writes the final state.
+//
+// Usage:
+//
+//	gameoflife [options] [pattern file]
+//
+// The pattern is read from the standard input when the file is "-" or it is missing.
+// The formats RLE, plaintext, Life 1.06 and macrocell are detected using the file content.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/davidnotplay/gameoflife/export"
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/hashlife"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/davidnotplay/gameoflife/pattern"
+	"github.com/davidnotplay/gameoflife/tui"
+)
+
+// Function that writes the final state of the game.
+type writerFunc func(w io.Writer, g *game.Game, c *config) error
+
+// Output formats, by name.
+var writers map[string]writerFunc = map[string]writerFunc{
+	"rle": func(w io.Writer, g *game.Game, c *config) error {
+		return pattern.WriteRLE(w, pattern.FromGame(g))
+	},
+	"cells": func(w io.Writer, g *game.Game, c *config) error {
+		return pattern.WriteCells(w, pattern.FromGame(g))
+	},
+	"life106": func(w io.Writer, g *game.Game, c *config) error {
+		return pattern.WriteLife106(w, pattern.FromGame(g))
+	},
+	"mc": func(w io.Writer, g *game.Game, c *config) error {
+		u, err := hashlife.FromGame(g)
+		if err != nil {
+			return err
+		}
+
+		return pattern.WriteMacrocell(w, u)
+	},
+	"png": func(w io.Writer, g *game.Game, c *config) error {
+		return export.WritePNG(w, g.GetMatrix(), export.ImageOptions{PPP: c.ppp, Grid: c.grid})
+	},
+	"svg": func(w io.Writer, g *game.Game, c *config) error {
+		options := export.SVGOptions{ImageOptions: export.ImageOptions{PPP: c.ppp, Grid: c.grid}}
+		return export.WriteSVG(w, g.GetMatrix(), options)
+	},
+	"none": func(w io.Writer, g *game.Game, c *config) error {
+		return nil
+	},
+}
+
+// Maximum size of the board sides when they are not set. The patterns that grow more reach
+// the board edges.
+const MAX_DEFAULT_SIZE int = 4096
+
+// Settings of the command.
+type config struct {
+	input     string
+	output    string
+	format    string
+	gens      uint
+	width     int
+	height    int
+	rule      string
+	boundary  string
+	neighbour string
+	topology  string
+	storage   string
+	workers   int
+	showStats bool
+
+	// Settings of the period detection.
+	detectPeriod bool
+	stopOnPeriod bool
+
+	// Settings of the images.
+	ppp  int
+	grid bool
+
+	// Settings of the terminal front end.
+	tui     bool
+	charset string
+	delay   time.Duration
+}
+
+// Returns the names of the output formats, sorted.
+func getFormatNames() []string {
+	names := []string{}
+	for name := range writers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Parses the command arguments `args`. The usage is written in `stderr`.
+func parseArgs(args []string, stderr io.Writer) (*config, error) {
+	c := &config{}
+	flags := flag.NewFlagSet("gameoflife", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	flags.UintVar(&c.gens, "gens", 100, "number of generations")
+	flags.IntVar(&c.width, "width", 0, "board width. By default, the pattern width plus the points reachable in the generations, up to 4096")
+	flags.IntVar(&c.height, "height", 0, "board height. By default, the pattern height plus the points reachable in the generations, up to 4096")
+	flags.StringVar(&c.rule, "rule", "", "rulestring, like B3/S23. By default, the pattern rule")
+	flags.StringVar(&c.neighbour, "neighbourhood", "moore", "adjacents of the points: moore, vonneumann, hexagonal or a mask of weights, like 010/101/010")
+	flags.StringVar(&c.topology, "topology", matrix.TOPOLOGY_SQUARE.String(), "shape of the cells: square, hexagonal or triangular. The hexagonal and triangular cells need the dense storage")
+	flags.StringVar(&c.boundary, "boundary", matrix.BOUNDARY_DEAD.String(), "boundary mode: dead, torus, klein, cylinder-x or cylinder-y")
+	flags.StringVar(&c.storage, "storage", matrix.STORAGE_DEFAULT.String(), "storage of the board: packed, dense or sparse. By default it is packed, or dense with the Generations and the Larger than Life rules")
+	flags.IntVar(&c.workers, "workers", 1, "number of goroutines used in the generations")
+	flags.StringVar(&c.output, "o", "-", "output file. \"-\" is the standard output")
+	flags.StringVar(&c.format, "format", "rle", "output format: "+strings.Join(getFormatNames(), ", "))
+	flags.IntVar(&c.ppp, "ppp", export.DEFAULT_PPP, "pixels per point of the png and svg formats")
+	flags.BoolVar(&c.grid, "grid", false, "draw the grid lines in the png and svg formats")
+	flags.BoolVar(&c.detectPeriod, "period", false, "detect the period of the pattern and write it in the statistics. The spaceships are only detected in the sparse storage")
+	flags.BoolVar(&c.stopOnPeriod, "stop-on-period", false, "stop the generations once the period is detected")
+	flags.BoolVar(&c.showStats, "stats", false, "write the statistics in the standard error")
+	flags.BoolVar(&c.tui, "tui", false, "animate the game in the terminal instead of running the generations. The final state is written only with -o")
+	flags.StringVar(&c.charset, "charset", tui.CHARSET_HALF_BLOCK.String(), "characters used in the terminal: half or braille")
+	flags.DurationVar(&c.delay, "delay", tui.DEFAULT_DELAY, "time between two generations in the terminal")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch flags.NArg() {
+	case 0:
+		c.input = "-"
+	case 1:
+		c.input = flags.Arg(0)
+	default:
+		return nil, errors.New("Too many pattern files.")
+	}
+
+	if _, ok := writers[c.format]; !ok {
+		return nil, fmt.Errorf("The output format \"%s\" is invalid.", c.format)
+	}
+
+	return c, nil
+}
+
+// Reads the pattern of the file `name`. "-" is `stdin`.
+func readPattern(name string, stdin io.Reader) (*pattern.Pattern, error) {
+	if name == "-" {
+		return pattern.Read(stdin)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return pattern.Read(f)
+}
+
+// Returns the default size of a board side for a pattern side of size `size` that grows
+// `radius` points per generation in each direction, in `gens` generations.
+// The size is limited to `MAX_DEFAULT_SIZE`, or to the pattern size when it is bigger.
+func getDefaultSize(size, radius int, gens uint) int {
+	limit := MAX_DEFAULT_SIZE
+	if size > limit {
+		limit = size
+	}
+
+	// The generations are compared before multiplying them, so they can not overflow.
+	if gens > uint(limit-size)/uint(2*radius) {
+		return limit
+	}
+
+	return size + 2*radius*int(gens)
+}
+
+// Makes the game of the pattern `p` using the settings `c`.
+func newGame(p *pattern.Pattern, c *config) (*game.Game, error) {
+	var err error
+	options := game.Options{Workers: c.workers, DetectPeriod: c.detectPeriod, StopOnPeriod: c.stopOnPeriod}
+
+	if options.Boundary, err = matrix.ParseBoundary(c.boundary); err != nil {
+		return nil, err
+	}
+
+	if options.Storage, err = matrix.ParseStorage(c.storage); err != nil {
+		return nil, err
+	}
+
+	if options.Topology, err = matrix.ParseTopology(c.topology); err != nil {
+		return nil, err
+	}
+
+	if c.rule != "" {
+		options.Rule, err = game.ParseRule(c.rule)
+	} else {
+		options.Rule, err = p.GetRule()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Neighbourhood, err = game.ParseNeighbourhood(c.neighbour); err != nil {
+		return nil, err
+	}
+
+	// The patterns can not grow faster than the greatest radius of the neighbourhood, the
+	// topology and the rule per generation in each direction.
+	radius := options.Neighbourhood.GetRadius()
+	for _, r := range []int{options.Topology.GetRadius(), options.Rule.GetRadius()} {
+		if r > radius {
+			radius = r
+		}
+	}
+
+	width, height := c.width, c.height
+	if width == 0 {
+		width = getDefaultSize(p.Width, radius, c.gens)
+	}
+
+	if height == 0 {
+		height = getDefaultSize(p.Height, radius, c.gens)
+	}
+
+	if width < matrix.MINIMUM_SIZE {
+		width = matrix.MINIMUM_SIZE
+	}
+
+	if height < matrix.MINIMUM_SIZE {
+		height = matrix.MINIMUM_SIZE
+	}
+
+	return p.NewGame(width, height, options)
+}
+
+// Writes the statistics of the game `g`, run with the settings `c`, in `w`.
+// `elapsed` is the time of the generations.
+func writeStats(w io.Writer, g *game.Game, c *config, elapsed time.Duration) {
+	m := g.GetMatrix()
+	fmt.Fprintf(w, "rule: %s\n", g.GetRule())
+	if n := g.GetNeighbourhood(); !n.IsMoore() {
+		fmt.Fprintf(w, "neighbourhood: %s\n", n)
+	}
+
+	if topology := g.GetTopology(); topology != matrix.TOPOLOGY_SQUARE {
+		fmt.Fprintf(w, "topology: %s\n", topology)
+	}
+
+	fmt.Fprintf(w, "generations: %d\n", g.GetCyclesNum())
+	fmt.Fprintf(w, "population: %d\n", m.GetPointsEnabled())
+
+	if dense, ok := m.(*matrix.Matrix); ok && g.GetRule().GetStates() > 2 {
+		dying := 0
+		for value := matrix.MATRIX_POINT_DYING; value < g.GetRule().GetStates(); value++ {
+			dying += dense.GetPointsWithValue(value)
+		}
+
+		fmt.Fprintf(w, "dying: %d\n", dying)
+	}
+
+	if box, ok := m.GetBoundingBox(); ok {
+		width, height := box.GetSize()
+		fmt.Fprintf(w, "bounding box: %s (%dx%d)\n", box, width, height)
+	} else {
+		fmt.Fprintf(w, "bounding box: empty\n")
+	}
+
+	if period, start, ok := g.GetPeriod(); ok {
+		fmt.Fprintf(w, "period: %d (from generation %d)\n", period, start)
+		if g.IsSpaceship() {
+			dx, dy, _ := g.GetDisplacement()
+			fmt.Fprintf(w, "spaceship: %s, displacement (%d, %d)\n", g.GetSpeed(), dx, dy)
+		}
+	} else if c.detectPeriod || c.stopOnPeriod {
+		fmt.Fprintf(w, "period: not found\n")
+	}
+
+	fmt.Fprintf(w, "elapsed: %s\n", elapsed)
+	if elapsed > 0 {
+		fmt.Fprintf(w, "speed: %.1f generations/s\n", float64(g.GetCyclesNum())/elapsed.Seconds())
+	}
+}
+
+// Runs the command with the arguments `args`. Returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c, err := parseArgs(args, stderr)
+	if err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	p, err := readPattern(c.input, stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	var tty *os.File
+	if c.tui {
+		if tty, err = os.OpenFile("/dev/tty", os.O_RDWR, 0); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		defer tty.Close()
+
+		if err := setTerminalSize(c, tty); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+
+	g, err := newGame(p, c)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	start := time.Now()
+	if c.tui {
+		err = runTerminal(g, c, tty)
+	} else {
+		_, err = g.Run(c.gens)
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	elapsed := time.Since(start)
+
+	if c.tui && c.output == "-" {
+		// The terminal is used by the front end.
+		if c.showStats {
+			writeStats(stderr, g, c, elapsed)
+		}
+
+		return 0
+	}
+
+	out := stdout
+	var file *os.File
+	if c.output != "-" {
+		if file, err = os.Create(c.output); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+
+		out = file
+	}
+
+	err = writers[c.format](out, g, c)
+	if file != nil {
+		// Some errors writing the file are only reported when it is closed.
+		if e := file.Close(); err == nil {
+			err = e
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if c.showStats {
+		writeStats(stderr, g, c, elapsed)
+	}
+
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const gliderRLE string = "#N Glider\nx = 3, y = 3, rule = B3/S23\nbob$2bo$3o!\n"
+
+// Runs the command with the arguments `args` and the standard input `stdin`.
+// Returns the exit code, the standard output and the standard error.
+func auxRun(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// Test the command runs the generations and writes the final state.
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	// The glider has the same shape after 4 generations.
+	code, stdout, stderr := auxRun(gliderRLE, "-gens", "4", "-format", "cells")
+	assert.Equal(code, 0, "Invalid exit code.")
+	assert.Equal(stderr, "", "There is an error.")
+	assert.Equal(stdout, ".O\n..O\nOOO\n", "Invalid output.")
+
+	// The blinker changes its phase.
+	for _, storage := range []string{"packed", "dense", "sparse"} {
+		code, stdout, _ = auxRun("OOO\n", "-gens", "1", "-format", "life106", "-storage", storage)
+		assert.Equal(code, 0, fmt.Sprintf("Invalid exit code with the storage %s.", storage))
+		assert.Equal(stdout, "#Life 1.06\n0 0\n0 1\n0 2\n", fmt.Sprintf("Invalid output with the storage %s.", storage))
+	}
+
+	code, stdout, _ = auxRun(gliderRLE, "-gens", "0", "-format", "png", "-width", "10", "-height", "10", "-ppp", "2")
+	assert.Equal(code, 0, "Invalid exit code.")
+	img, err := png.Decode(strings.NewReader(stdout))
+	assert.Equal(err, nil, "Invalid png output.")
+	assert.Equal(img.Bounds().Dx(), 20, "Invalid png width.")
+
+	code, stdout, _ = auxRun(gliderRLE, "-gens", "0", "-format", "svg", "-width", "10", "-height", "10", "-ppp", "2")
+	assert.Equal(code, 0, "Invalid exit code.")
+	assert.Equal(strings.Count(stdout, `width="2" height="2"/>`), 5, "Invalid svg output.")
+
+	code, stdout, _ = auxRun(gliderRLE, "-gens", "0", "-format", "mc")
+	assert.Equal(code, 0, "Invalid exit code.")
+	assert.Equal(strings.HasPrefix(stdout, "[M2]"), true, "Invalid macrocell output.")
+}
+
+// Test the options of the board.
+func TestRunOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	// In a torus of 10x10 the glider comes back to the same position after 40 generations.
+	code, stdout, _ := auxRun(gliderRLE, "-gens", "40", "-width", "10", "-height", "10", "-boundary", "torus")
+	assert.Equal(code, 0, "Invalid exit code.")
+	assert.Equal(stdout, "x = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n", "Invalid output in the torus.")
+
+	// In a dead board the glider becomes a block in the corner.
+	code, stdout, _ = auxRun(gliderRLE, "-gens", "40", "-width", "10", "-height", "10")
+	assert.Equal(code, 0, "Invalid exit code.")
+	assert.Equal(stdout, "x = 2, y = 2, rule = B3/S23\n2o$2o!\n", "Invalid output in the dead board.")
+
+	// The rule of the command has priority.
+	code, stdout, _ = auxRun(gliderRLE, "-gens", "0", "-rule", "B36/S23")
+	assert.Equal(code, 0, "Invalid exit code.")
+	assert.Equal(stdout, "x = 3, y = 3, rule = B36/S23\nbo$2bo$3o!\n", "Invalid output with the rule.")
+
+	// The Generations rules need the dense storage.
+	code, _, stderr := auxRun("OO\n", "-gens", "1", "-rule", "B2/S/C3", "-storage", "dense", "-format", "none", "-stats")
+	assert.Equal(code, 0, "Invalid exit code with the Generations rule.")
+	assert.Equal(strings.Contains(stderr, "population: 4\ndying: 2\n"), true, "Invalid dying points.")
+
+	code, _, stderr = auxRun("OO\n", "-gens", "1", "-rule", "B2/S/C3", "-format", "none", "-stats")
+	assert.Equal(code, 0, "Invalid exit code with the Generations rule in the default storage.")
+	assert.Equal(strings.Contains(stderr, "population: 4\ndying: 2\n"), true, "Invalid dying points in the default storage.")
+
+	code, _, _ = auxRun("OO\n", "-rule", "B2/S/C3", "-storage", "packed")
+	assert.Equal(code, 1, "Invalid exit code with the Generations rule in the packed storage.")
+
+	// The Larger than Life rules use the dense storage by default too.
+	code, _, _ = auxRun("O\n", "-gens", "1", "-rule", "R5,C0,M1,S34..58,B34..45,NM", "-format", "none")
+	assert.Equal(code, 0, "Invalid exit code with the Larger than Life rule in the default storage.")
+
+	// In the von Neumann neighbourhood a point makes a cross.
+	code, stdout, stderr = auxRun("O\n", "-gens", "1", "-rule", "B1/S", "-neighbourhood", "vonneumann", "-format", "cells", "-stats")
+	assert.Equal(code, 0, "Invalid exit code with the neighbourhood.")
+	assert.Equal(stdout, ".O\nO.O\n.O\n", "Invalid output with the neighbourhood.")
+	assert.Equal(strings.Contains(stderr, "neighbourhood: 010/101/010\n"), true, "The statistics have not the neighbourhood.")
+
+	// In the hexagonal topology a point enables its 6 adjacents.
+	code, stdout, stderr = auxRun("O\n", "-gens", "1", "-rule", "B1/S", "-topology", "hexagonal", "-storage", "dense", "-format", "cells", "-stats")
+	assert.Equal(code, 0, "Invalid exit code with the topology.")
+	assert.Equal(stdout, ".OO\nO.O\nOO\n", "Invalid output with the topology.")
+	assert.Equal(strings.Contains(stderr, "topology: hexagonal\n"), true, "The statistics have not the topology.")
+}
+
+// Test the default size of the board.
+func TestGetDefaultSize(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		size, radius int
+		gens         uint
+		expected     int
+	}{
+		{3, 1, 100, 203},
+		{3, 2, 100, 403},
+		{3, 1, 100000, MAX_DEFAULT_SIZE},
+		{3, 1, ^uint(0), MAX_DEFAULT_SIZE},
+		{3, 10, ^uint(0), MAX_DEFAULT_SIZE},
+		{5000, 1, 10, 5000},
+		{MAX_DEFAULT_SIZE - 10, 1, 5, MAX_DEFAULT_SIZE},
+	}
+
+	for _, test := range tests {
+		size := getDefaultSize(test.size, test.radius, test.gens)
+		assert.Equal(size, test.expected, fmt.Sprintf("Invalid size of %d in %d generations.", test.size, test.gens))
+	}
+
+	c, _ := parseArgs([]string{"-gens", "100000"}, ioutil.Discard)
+	p, _ := readPattern("-", strings.NewReader(gliderRLE))
+	g, err := newGame(p, c)
+	assert.Equal(err, nil, "There is an error.")
+	w, h := g.GetMatrix().GetSize()
+	assert.Equal([2]int{w, h}, [2]int{MAX_DEFAULT_SIZE, MAX_DEFAULT_SIZE}, "The size is not limited.")
+
+	// The Larger than Life rules grow up to their radius in each generation.
+	c, _ = parseArgs([]string{"-gens", "10", "-rule", "R5,C0,M1,S34..58,B34..45,NM"}, ioutil.Discard)
+	g, err = newGame(p, c)
+	assert.Equal(err, nil, "There is an error with the Larger than Life rule.")
+	w, h = g.GetMatrix().GetSize()
+	assert.Equal([2]int{w, h}, [2]int{3 + 2*5*10, 3 + 2*5*10}, "Invalid size with the Larger than Life rule.")
+}
+
+// Test the files and the statistics.
+func TestRunFiles(t *testing.T) {
+	assert := assert.New(t)
+	dir, _ := ioutil.TempDir("", "gameoflife")
+	defer os.RemoveAll(dir)
+
+	input, output := filepath.Join(dir, "glider.rle"), filepath.Join(dir, "out.cells")
+	ioutil.WriteFile(input, []byte(gliderRLE), 0644)
+
+	code, stdout, stderr := auxRun("", "-gens", "8", "-format", "cells", "-o", output, "-stats", "-workers", "4", input)
+	assert.Equal(code, 0, "Invalid exit code.")
+	assert.Equal(stdout, "", "The standard output is not empty.")
+
+	data, _ := ioutil.ReadFile(output)
+	assert.Equal(string(data), ".O\n..O\nOOO\n", "Invalid output file.")
+
+	for _, line := range []string{"rule: B3/S23\n", "generations: 8\n", "population: 5\n", "bounding box: (10, 10)-(12, 12) (3x3)\n"} {
+		assert.Equal(strings.Contains(stderr, line), true, fmt.Sprintf("The statistics have not %q.", line))
+	}
+}
+
+// Test the period detection.
+func TestRunPeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	code, _, stderr := auxRun("OOO\n", "-gens", "100", "-stop-on-period", "-stats")
+	assert.Equal(code, 0, "Invalid exit code.")
+	assert.Equal(strings.Contains(stderr, "generations: 2\n"), true, "The generations have not stopped.")
+	assert.Equal(strings.Contains(stderr, "period: 2 (from generation 0)\n"), true, "Invalid period.")
+
+	// The spaceships are only detected in the unbounded storages.
+	_, _, stderr = auxRun(gliderRLE, "-gens", "10", "-period", "-stats", "-storage", "sparse")
+	assert.Equal(strings.Contains(stderr, "period: 4 (from generation 0)\n"), true, "Invalid period of the glider.")
+	assert.Equal(strings.Contains(stderr, "spaceship: c/4 diagonal, displacement (1, 1)\n"), true, "Invalid speed of the glider.")
+
+	// R-pentomino.
+	_, _, stderr = auxRun("x = 3, y = 3\nb2o$2o$bo!\n", "-gens", "10", "-period", "-stats")
+	assert.Equal(strings.Contains(stderr, "period: not found\n"), true, "The period of the R-pentomino is found.")
+}
+
+// Test the errors of the command.
+func TestRunError(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		args   []string
+		stdin  string
+		code   int
+		stderr string
+	}{
+		{[]string{"-format", "bmp"}, gliderRLE, 2, "The output format \"bmp\" is invalid.\n"},
+		{[]string{"-format", "png", "-ppp", "1", "-grid"}, gliderRLE, 1, "The pixels per point (1) are invalid. Minimum 2.\n"},
+		{[]string{"a.rle", "b.rle"}, gliderRLE, 2, "Too many pattern files.\n"},
+		{[]string{"-boundary", "sphere"}, gliderRLE, 1, "The boundary mode \"sphere\" is invalid.\n"},
+		{[]string{"-rule", "B9/S23"}, gliderRLE, 1, "The rule \"B9/S23\" is invalid: invalid character '9'.\n"},
+		{[]string{"-neighbourhood", "0x0/101/010"}, gliderRLE, 1, "The neighbourhood is invalid: invalid character 'x'.\n"},
+		{[]string{"-topology", "cube"}, gliderRLE, 1, "The topology \"cube\" is invalid.\n"},
+		{[]string{"-topology", "triangular"}, gliderRLE, 1, "The triangular topology is not allowed: it needs the dense storage.\n"},
+		{[]string{}, "Hello world", 1, "The pattern format is unknown.\n"},
+		{[]string{"-storage", "sparse", "-rule", "B3/S23"}, "#Life 1.06\n0 0 0", 1, "Invalid Life 1.06 pattern. Line 2, column 1: invalid coordinates \"0 0 0\".\n"},
+	}
+
+	for _, test := range tests {
+		code, _, stderr := auxRun(test.stdin, test.args...)
+		assert.Equal(code, test.code, fmt.Sprintf("Invalid exit code of %v.", test.args))
+		assert.Equal(stderr, test.stderr, fmt.Sprintf("Invalid error of %v.", test.args))
+	}
+
+	code, _, _ := auxRun("", "/not/found.rle")
+	assert.Equal(code, 1, "Invalid exit code with a missing file.")
+
+	code, _, stderr := auxRun("", "-h")
+	assert.Equal(code, 0, "Invalid exit code of the help.")
+	assert.Equal(strings.Contains(stderr, "-gens"), true, "The help is not written.")
+}
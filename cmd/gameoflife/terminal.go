@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/davidnotplay/gameoflife/tui"
+)
+
+// Sets the board size, when it is not set, to the size of the terminal `tty`.
+// The last row of the terminal is used by the status line.
+func setTerminalSize(c *config, tty *os.File) error {
+	charset, err := tui.ParseCharset(c.charset)
+	if err != nil {
+		return err
+	}
+
+	cols, rows, err := tui.GetSize(tty)
+	if err != nil {
+		return err
+	}
+
+	cw, ch := charset.GetCellSize()
+	if c.width == 0 {
+		c.width = cols * cw
+	}
+
+	if c.height == 0 {
+		c.height = (rows - 1) * ch
+	}
+
+	return nil
+}
+
+// Animates the game `g` in the terminal `tty` until the user quits.
+func runTerminal(g *game.Game, c *config, tty *os.File) error {
+	charset, err := tui.ParseCharset(c.charset)
+	if err != nil {
+		return err
+	}
+
+	restore, err := tui.MakeRaw(tty)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	t := tui.New(g, tty, charset)
+	t.SetDelay(c.delay)
+
+	// The unbounded matrices are drawn around the pattern, using the board size.
+	if m := g.GetMatrix(); !m.IsBounded() {
+		box, _ := m.GetBoundingBox()
+		x, y := (box.MinX+box.MaxX-c.width)/2, (box.MinY+box.MaxY-c.height)/2
+		t.SetViewport(matrix.Rect{MinX: x, MinY: y, MaxX: x + c.width - 1, MaxY: y + c.height - 1})
+	}
+
+	return t.Run(tty)
+}
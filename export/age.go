@@ -0,0 +1,50 @@
+package export
+
+import (
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Number of consecutive cycles that each point is enabled. Used to color the points by age.
+type AgeTracker struct {
+	ages map[game.Position]int
+}
+
+// Makes a new tracker without points.
+func NewAgeTracker() *AgeTracker {
+	return &AgeTracker{map[game.Position]int{}}
+}
+
+// Updates the ages with the points enabled of the universe `m`. It must be called after
+// each cycle: the points enabled get older and the points disabled are removed.
+func (self *AgeTracker) Update(m matrix.Universe) {
+	ages := make(map[game.Position]int, len(self.ages))
+	m.ForEachEnabled(func(x, y int) {
+		p := game.Position{x, y}
+		ages[p] = self.ages[p] + 1
+	})
+
+	self.ages = ages
+}
+
+// Returns the number of updates that the point `x`, `y` is enabled. Zero when it is disabled.
+func (self *AgeTracker) GetAge(x, y int) int {
+	return self.ages[game.Position{x, y}]
+}
+
+// Returns a function, valid as `SVGOptions.PointColor`, that returns the color `colors[age - 1]`
+// of each point. The points older than the colors use the last color.
+func (self *AgeTracker) GetColorFunc(colors []string) func(x, y int) string {
+	return func(x, y int) string {
+		age := self.GetAge(x, y)
+		if age == 0 || len(colors) == 0 {
+			return ""
+		}
+
+		if age > len(colors) {
+			age = len(colors)
+		}
+
+		return colors[age-1]
+	}
+}
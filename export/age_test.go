@@ -0,0 +1,33 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the functions Update and GetAge.
+func TestAgeTracker(t *testing.T) {
+	assert := assert.New(t)
+	// Blinker: the center is always enabled and the ends change each cycle.
+	g, _ := game.New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, []game.Position{{4, 5}, {5, 5}, {6, 5}})
+	tracker := NewAgeTracker()
+
+	tracker.Update(g.GetMatrix())
+	for i := 0; i < 3; i++ {
+		g.Cycle()
+		tracker.Update(g.GetMatrix())
+	}
+
+	assert.Equal(tracker.GetAge(5, 5), 4, "Invalid age of the center.")
+	assert.Equal(tracker.GetAge(5, 4), 1, "Invalid age of the end.")
+	assert.Equal(tracker.GetAge(4, 5), 0, "Invalid age of the point disabled.")
+
+	color := tracker.GetColorFunc([]string{"#fff", "#ccc", "#999"})
+	assert.Equal(color(5, 4), "#fff", "Invalid color of the young point.")
+	assert.Equal(color(5, 5), "#999", "Invalid color of the old point.")
+	assert.Equal(color(4, 5), "", "Invalid color of the point disabled.")
+	assert.Equal(tracker.GetColorFunc(nil)(5, 5), "", "Invalid color without colors.")
+}
@@ -0,0 +1,28 @@
+package export
+
+import (
+	"fmt"
+)
+
+type invalidColorError string
+
+func (self *invalidColorError) Error() string {
+	message := "The color \"%s\" is invalid. Format: #rrggbb or #rgb."
+	return fmt.Sprintf(message, string(*self))
+}
+
+func InvalidColorError(color string) error {
+	err := invalidColorError(color)
+	return &err
+}
+
+type invalidPPPError [2]int
+
+func (self *invalidPPPError) Error() string {
+	message := "The pixels per point (%d) are invalid. Minimum %d."
+	return fmt.Sprintf(message, self[0], self[1])
+}
+
+func InvalidPPPError(ppp, minimum int) error {
+	return &invalidPPPError{ppp, minimum}
+}
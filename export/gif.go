@@ -0,0 +1,103 @@
+package export
+
+import (
+	"image"
+	"image/gif"
+	"io"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Delay by default between two frames, in hundredths of second.
+const DEFAULT_DELAY int = 20
+
+// Settings of the animations.
+type GIFOptions struct {
+	ImageOptions
+
+	// Delay between two frames, in hundredths of second. `DEFAULT_DELAY` is used when it is zero.
+	Delay int
+
+	// Number of times the animation is repeated after the first time. Zero repeats it forever
+	// and -1 shows it once.
+	LoopCount int
+}
+
+// Points enabled in a game cycle.
+type frame []game.Position
+
+// Returns the points enabled in the universe `m`.
+func getFrame(m matrix.Universe) frame {
+	f := frame{}
+	m.ForEachEnabled(func(x, y int) {
+		f = append(f, game.Position{x, y})
+	})
+
+	return f
+}
+
+// Runs `cycles` cycles of the game `g` and writes the animation of them in GIF format in `w`.
+// The first frame is the current game state, so the animation has `cycles + 1` frames.
+// The bounded matrices are drawn entirely and the unbounded ones in the rectangle with the
+// points of all the cycles.
+// Returns an error whether the settings are invalid or the game returns an error.
+func WriteGIF(w io.Writer, g *game.Game, cycles int, options GIFOptions) error {
+	palette, err := options.getPalette()
+	if err != nil {
+		return err
+	}
+
+	if _, err := options.getPPP(); err != nil {
+		return err
+	}
+
+	delay := options.Delay
+	if delay == 0 {
+		delay = DEFAULT_DELAY
+	}
+
+	// The points of all the frames are saved, so the rectangle of the unbounded
+	// universes is known before drawing them.
+	m := g.GetMatrix()
+	frames := []frame{getFrame(m)}
+	rect := getUniverseRect(m)
+
+	for i := 0; i < cycles; i++ {
+		if err := g.Cycle(); err != nil {
+			return err
+		}
+
+		m = g.GetMatrix()
+		frames = append(frames, getFrame(m))
+
+		if !m.IsBounded() {
+			if box, ok := m.GetBoundingBox(); ok {
+				rect = rect.Extend(box.MinX, box.MinY).Extend(box.MaxX, box.MaxY)
+			}
+		}
+	}
+
+	anim := &gif.GIF{LoopCount: options.LoopCount}
+	for _, f := range frames {
+		enabled := make(map[game.Position]bool, len(f))
+		for _, p := range f {
+			enabled[p] = true
+		}
+
+		isEnabled := func(x, y int) bool {
+			return enabled[game.Position{x, y}]
+		}
+
+		anim.Image = append(anim.Image, drawImage(rect, isEnabled, options.ImageOptions, palette))
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	anim.Config = image.Config{
+		ColorModel: palette,
+		Width:      anim.Image[0].Bounds().Dx(),
+		Height:     anim.Image[0].Bounds().Dy(),
+	}
+
+	return gif.EncodeAll(w, anim)
+}
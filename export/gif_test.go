@@ -0,0 +1,68 @@
+package export
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function WriteGIF.
+func TestWriteGIF(t *testing.T) {
+	assert := assert.New(t)
+	// Blinker.
+	g, _ := game.New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, []game.Position{{4, 5}, {5, 5}, {6, 5}})
+
+	var b bytes.Buffer
+	err := WriteGIF(&b, g, 3, GIFOptions{ImageOptions: ImageOptions{PPP: 2}, Delay: 50, LoopCount: -1})
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(g.GetCyclesNum(), uint(3), "Invalid cycles.")
+
+	anim, err := gif.DecodeAll(&b)
+	assert.Equal(err, nil, "The GIF is invalid.")
+	assert.Equal(len(anim.Image), 4, "Invalid number of frames.")
+	assert.Equal(anim.Delay, []int{50, 50, 50, 50}, "Invalid delays.")
+	assert.Equal(anim.LoopCount, -1, "Invalid loop count.")
+	assert.Equal([2]int{anim.Config.Width, anim.Config.Height}, [2]int{20, 20}, "Invalid size.")
+
+	// Horizontal and vertical phases of the blinker.
+	assert.Equal(anim.Image[0].At(2*4, 2*5), yellow, "The first frame is not horizontal.")
+	assert.Equal(anim.Image[0].At(2*5, 2*4), grey, "The first frame is not horizontal.")
+	assert.Equal(anim.Image[1].At(2*5, 2*4), yellow, "The second frame is not vertical.")
+	assert.Equal(anim.Image[1].At(2*4, 2*5), grey, "The second frame is not vertical.")
+}
+
+// Test the function WriteGIF with an unbounded universe.
+func TestWriteGIFSparse(t *testing.T) {
+	assert := assert.New(t)
+	glider := []game.Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	g, _ := game.NewWithOptions(0, 0, glider, game.Options{Storage: matrix.STORAGE_SPARSE})
+
+	var b bytes.Buffer
+	err := WriteGIF(&b, g, 8, GIFOptions{ImageOptions: ImageOptions{PPP: 1}})
+	assert.Equal(err, nil, "There is an error.")
+
+	// The glider moves two points in 8 cycles, so all the frames have 5x5 points.
+	anim, _ := gif.DecodeAll(&b)
+	assert.Equal(len(anim.Image), 9, "Invalid number of frames.")
+	assert.Equal(anim.Delay[0], DEFAULT_DELAY, "Invalid default delay.")
+	for _, img := range anim.Image {
+		assert.Equal([2]int{img.Bounds().Dx(), img.Bounds().Dy()}, [2]int{5, 5}, "Invalid frame size.")
+	}
+
+	assert.Equal(anim.Image[8].At(4, 4), yellow, "The glider has not moved.")
+}
+
+// Test the errors of the function WriteGIF.
+func TestWriteGIFError(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := game.New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, []game.Position{})
+
+	var b bytes.Buffer
+	err := WriteGIF(&b, g, 3, GIFOptions{ImageOptions: ImageOptions{Palette: Palette{Enabled: "yellow"}}})
+	assert.Equal(err, InvalidColorError("yellow"), "Invalid error.")
+	assert.Equal(g.GetCyclesNum(), uint(0), "The game has run cycles.")
+}
@@ -0,0 +1,197 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Pixels per point by default. The same as the canvas of the browser.
+const DEFAULT_PPP int = 14
+
+// Colors of the images, in format "#rrggbb" or "#rgb".
+type Palette struct {
+	// Color of the points disabled.
+	Disabled string
+
+	// Color of the points enabled.
+	Enabled string
+
+	// Color of the grid lines.
+	Grid string
+}
+
+// Palette by default. The same colors as the canvas of the browser.
+var DEFAULT_PALETTE Palette = Palette{Disabled: "#666666", Enabled: "#ffeb3b", Grid: "#000000"}
+
+// Indexes of the colors in the paletted images.
+const (
+	colorDisabled uint8 = iota
+	colorEnabled
+	colorGrid
+)
+
+// Settings of the images.
+type ImageOptions struct {
+	// Pixels per point. `DEFAULT_PPP` is used when it is zero. Minimum 2 with the grid.
+	PPP int
+
+	// Whether draws the grid lines around the points. The lines are one pixel wide.
+	Grid bool
+
+	// Colors of the image. The colors of `DEFAULT_PALETTE` are used when they are empty.
+	Palette Palette
+}
+
+// Parses the color `s` in format "#rrggbb" or "#rgb".
+// Returns an error whether the color is malformed.
+func ParseColor(s string) (color.RGBA, error) {
+	hex := s
+	if len(hex) > 0 && hex[0] == '#' {
+		hex = hex[1:]
+	}
+
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) != 6 || s[0] != '#' {
+		return color.RGBA{}, InvalidColorError(s)
+	}
+
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, InvalidColorError(s)
+	}
+
+	return color.RGBA{uint8(n >> 16), uint8(n >> 8), uint8(n), 0xff}, nil
+}
+
+// Returns the color palette of the images made with the settings `self`.
+func (self ImageOptions) getPalette() (color.Palette, error) {
+	p := color.Palette{}
+
+	for _, c := range [3][2]string{
+		{self.Palette.Disabled, DEFAULT_PALETTE.Disabled},
+		{self.Palette.Enabled, DEFAULT_PALETTE.Enabled},
+		{self.Palette.Grid, DEFAULT_PALETTE.Grid},
+	} {
+		if c[0] == "" {
+			c[0] = c[1]
+		}
+
+		rgba, err := ParseColor(c[0])
+		if err != nil {
+			return nil, err
+		}
+
+		p = append(p, rgba)
+	}
+
+	return p, nil
+}
+
+// Returns the pixels per point of the settings `self`.
+// With the grid lines the points need two pixels at least.
+func (self ImageOptions) getPPP() (int, error) {
+	if self.PPP == 0 {
+		return DEFAULT_PPP, nil
+	}
+
+	minimum := 1
+	if self.Grid {
+		minimum = 2
+	}
+
+	if self.PPP < minimum {
+		return 0, InvalidPPPError(self.PPP, minimum)
+	}
+
+	return self.PPP, nil
+}
+
+// Returns the rectangle of the universe `m` drawn in the images: all the points of the
+// bounded universes and the bounding box of the unbounded ones.
+func getUniverseRect(m matrix.Universe) matrix.Rect {
+	if m.IsBounded() {
+		return matrix.Rect{MaxX: m.GetWidth() - 1, MaxY: m.GetHeight() - 1}
+	}
+
+	box, _ := m.GetBoundingBox()
+	return box
+}
+
+// Draws the points of the rectangle `rect` in a new image. `isEnabled` returns if a point
+// is enabled.
+func drawImage(rect matrix.Rect, isEnabled func(x, y int) bool, options ImageOptions, palette color.Palette) *image.Paletted {
+	ppp, _ := options.getPPP()
+	w, h := rect.GetSize()
+
+	// With the grid, the cells have a line at the top and the left, and the image a line
+	// at the right and the bottom.
+	size, offset, border := ppp, 0, 0
+	if options.Grid {
+		size, offset, border = ppp-1, 1, 1
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, w*ppp+border, h*ppp+border), palette)
+	if options.Grid {
+		for i := range img.Pix {
+			img.Pix[i] = colorGrid
+		}
+	}
+
+	for px := 0; px < w; px++ {
+		for py := 0; py < h; py++ {
+			index := colorDisabled
+			if isEnabled(rect.MinX+px, rect.MinY+py) {
+				index = colorEnabled
+			}
+
+			x0, y0 := px*ppp+offset, py*ppp+offset
+			for y := y0; y < y0+size; y++ {
+				row := img.Pix[y*img.Stride:]
+				for x := x0; x < x0+size; x++ {
+					row[x] = index
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+// Returns the image of the universe `m` made with the settings `options`.
+// The bounded universes are drawn entirely and the unbounded ones in their bounding box.
+// Returns an error whether the settings are invalid.
+func Image(m matrix.Universe, options ImageOptions) (*image.Paletted, error) {
+	palette, err := options.getPalette()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := options.getPPP(); err != nil {
+		return nil, err
+	}
+
+	isEnabled := func(x, y int) bool {
+		enabled, err := m.IsEnabled(x, y)
+		return err == nil && enabled
+	}
+
+	return drawImage(getUniverseRect(m), isEnabled, options, palette), nil
+}
+
+// Writes the image of the universe `m` in PNG format in `w`. See `Image`.
+func WritePNG(w io.Writer, m matrix.Universe, options ImageOptions) error {
+	img, err := Image(m, options)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
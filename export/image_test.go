@@ -0,0 +1,123 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+var yellow color.RGBA = color.RGBA{0xff, 0xeb, 0x3b, 0xff}
+var grey color.RGBA = color.RGBA{0x66, 0x66, 0x66, 0xff}
+var black color.RGBA = color.RGBA{0, 0, 0, 0xff}
+
+// Test the function ParseColor.
+func TestParseColor(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[string]color.RGBA{
+		"#ffeb3b": yellow,
+		"#FFEB3B": yellow,
+		"#666":    grey,
+	}
+
+	for s, expected := range tests {
+		c, err := ParseColor(s)
+		assert.Equal(err, nil, fmt.Sprintf("There is an error with %s.", s))
+		assert.Equal(c, expected, fmt.Sprintf("Invalid color %s.", s))
+	}
+
+	for _, s := range []string{"", "#", "ffeb3b", "#ffeb3", "#gggggg", "#ffeb3bff"} {
+		_, err := ParseColor(s)
+		assert.Equal(err, InvalidColorError(s), fmt.Sprintf("Invalid error of %q.", s))
+	}
+}
+
+// Test the function Image.
+func TestImage(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := matrix.New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE)
+	m.EnablePoint(1, 2)
+
+	img, err := Image(m, ImageOptions{PPP: 3})
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(img.Bounds().Dx(), 30, "Invalid width.")
+	assert.Equal(img.Bounds().Dy(), 30, "Invalid height.")
+
+	for _, p := range [][2]int{{3, 6}, {5, 8}} {
+		assert.Equal(img.At(p[0], p[1]), yellow, fmt.Sprintf("The pixel %dx%d is not enabled.", p[0], p[1]))
+	}
+
+	for _, p := range [][2]int{{2, 6}, {6, 8}, {0, 0}, {29, 29}} {
+		assert.Equal(img.At(p[0], p[1]), grey, fmt.Sprintf("The pixel %dx%d is not disabled.", p[0], p[1]))
+	}
+}
+
+// Test the function Image with the grid lines and the palette.
+func TestImageGrid(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := matrix.New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE)
+	m.EnablePoint(0, 0)
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+
+	img, err := Image(m, ImageOptions{PPP: 4, Grid: true, Palette: Palette{Enabled: "#fff"}})
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(img.Bounds().Dx(), 41, "Invalid width.")
+
+	assert.Equal(img.At(0, 0), black, "The grid is not drawn in the corner.")
+	assert.Equal(img.At(4, 2), black, "The grid is not drawn between the points.")
+	assert.Equal(img.At(40, 40), black, "The grid is not drawn in the border.")
+	assert.Equal(img.At(1, 1), white, "The point is not enabled.")
+	assert.Equal(img.At(3, 3), white, "The point is not enabled.")
+	assert.Equal(img.At(5, 5), grey, "The point is not disabled.")
+}
+
+// Test the function Image with an unbounded universe.
+func TestImageSparse(t *testing.T) {
+	assert := assert.New(t)
+	m := matrix.NewSparse()
+	m.EnablePoint(-5, 10)
+	m.EnablePoint(-3, 11)
+
+	img, _ := Image(m, ImageOptions{PPP: 1})
+	assert.Equal([2]int{img.Bounds().Dx(), img.Bounds().Dy()}, [2]int{3, 2}, "Invalid size.")
+	assert.Equal(img.At(0, 0), yellow, "The first point is not enabled.")
+	assert.Equal(img.At(2, 1), yellow, "The second point is not enabled.")
+	assert.Equal(img.At(1, 0), grey, "The point is not disabled.")
+}
+
+// Test the errors of the function Image.
+func TestImageError(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := matrix.New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE)
+
+	_, err := Image(m, ImageOptions{PPP: -1})
+	assert.Equal(err, InvalidPPPError(-1, 1), "Invalid error.")
+	assert.Equal(err.Error(), "The pixels per point (-1) are invalid. Minimum 1.", "Invalid error message.")
+
+	_, err = Image(m, ImageOptions{PPP: 1, Grid: true})
+	assert.Equal(err, InvalidPPPError(1, 2), "Invalid error with the grid.")
+
+	_, err = Image(m, ImageOptions{Palette: Palette{Grid: "black"}})
+	assert.Equal(err, InvalidColorError("black"), "Invalid color error.")
+}
+
+// Test the function WritePNG.
+func TestWritePNG(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := matrix.New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE)
+	m.EnablePoint(9, 9)
+
+	var b bytes.Buffer
+	assert.Equal(WritePNG(&b, m, ImageOptions{}), nil, "There is an error.")
+
+	img, err := png.Decode(&b)
+	assert.Equal(err, nil, "The PNG is invalid.")
+	assert.Equal(img.Bounds().Dx(), 10*DEFAULT_PPP, "Invalid width.")
+
+	r, g, bl, _ := img.At(10*DEFAULT_PPP-1, 10*DEFAULT_PPP-1).RGBA()
+	assert.Equal([3]uint32{r >> 8, g >> 8, bl >> 8}, [3]uint32{0xff, 0xeb, 0x3b}, "The last point is not enabled.")
+}
@@ -0,0 +1,139 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Distance by default between two coordinate labels, in points.
+const DEFAULT_LABEL_STEP int = 5
+
+// Settings of the SVG images. The sizes are in user units, so the images can be scaled.
+type SVGOptions struct {
+	ImageOptions
+
+	// Rectangle of the universe drawn. When it is nil the bounded universes are drawn
+	// entirely and the unbounded ones in their bounding box.
+	Region *matrix.Rect
+
+	// Whether draws the coordinates of the points at the top and the left of the image.
+	Labels bool
+
+	// Distance between two labels, in points. `DEFAULT_LABEL_STEP` is used when it is zero.
+	LabelStep int
+
+	// Returns the color of the point enabled `x`, `y`. Used to color the points by age,
+	// for example. The palette color is used when it is nil or it returns an empty string.
+	PointColor func(x, y int) string
+}
+
+// Returns the color, in format "#rrggbb", of `c`.
+func formatColor(c [3]uint8) string {
+	return fmt.Sprintf("#%02x%02x%02x", c[0], c[1], c[2])
+}
+
+// Writes the image of the universe `m` in SVG format in `w`.
+// Returns an error whether the settings are invalid.
+func WriteSVG(w io.Writer, m matrix.Universe, options SVGOptions) error {
+	palette, err := options.getPalette()
+	if err != nil {
+		return err
+	}
+
+	ppp, err := options.getPPP()
+	if err != nil {
+		return err
+	}
+
+	colors := [3]string{}
+	for i, c := range palette {
+		r, g, b, _ := c.RGBA()
+		colors[i] = formatColor([3]uint8{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)})
+	}
+
+	rect := getUniverseRect(m)
+	if options.Region != nil {
+		rect = *options.Region
+	}
+
+	step := options.LabelStep
+	if step <= 0 {
+		step = DEFAULT_LABEL_STEP
+	}
+
+	// The labels are drawn in a margin of one point.
+	margin := 0
+	if options.Labels {
+		margin = ppp
+	}
+
+	pw, ph := rect.GetSize()
+	width, height := pw*ppp+margin, ph*ppp+margin
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+		margin, margin, pw*ppp, ph*ppp, colors[colorDisabled])
+
+	// Points enabled.
+	fmt.Fprintf(bw, `<g fill="%s">`+"\n", colors[colorEnabled])
+	for y := rect.MinY; y <= rect.MaxY; y++ {
+		for x := rect.MinX; x <= rect.MaxX; x++ {
+			if enabled, err := m.IsEnabled(x, y); err != nil || !enabled {
+				continue
+			}
+
+			px, py := margin+(x-rect.MinX)*ppp, margin+(y-rect.MinY)*ppp
+			fill := ""
+			if options.PointColor != nil {
+				// The color is escaped, so it can not break the attribute.
+				if c := options.PointColor(x, y); c != "" {
+					fill = fmt.Sprintf(` fill="%s"`, html.EscapeString(c))
+				}
+			}
+
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d"%s/>`+"\n", px, py, ppp, ppp, fill)
+		}
+	}
+	bw.WriteString("</g>\n")
+
+	if options.Grid {
+		fmt.Fprintf(bw, `<path stroke="%s" stroke-width="1" fill="none" d="`, colors[colorGrid])
+		for i := 0; i <= pw; i++ {
+			fmt.Fprintf(bw, "M%d %dv%d", margin+i*ppp, margin, ph*ppp)
+		}
+
+		for i := 0; i <= ph; i++ {
+			fmt.Fprintf(bw, "M%d %dh%d", margin, margin+i*ppp, pw*ppp)
+		}
+		bw.WriteString(`"/>` + "\n")
+	}
+
+	if options.Labels {
+		fmt.Fprintf(bw, `<g font-family="monospace" font-size="%g" text-anchor="middle" dominant-baseline="central">`+"\n",
+			float64(ppp)*0.6)
+
+		for x := rect.MinX; x <= rect.MaxX; x++ {
+			if x%step == 0 {
+				px := float64(margin+(x-rect.MinX)*ppp) + float64(ppp)/2
+				fmt.Fprintf(bw, `<text x="%g" y="%g">%d</text>`+"\n", px, float64(ppp)/2, x)
+			}
+		}
+
+		for y := rect.MinY; y <= rect.MaxY; y++ {
+			if y%step == 0 {
+				py := float64(margin+(y-rect.MinY)*ppp) + float64(ppp)/2
+				fmt.Fprintf(bw, `<text x="%g" y="%g">%d</text>`+"\n", float64(ppp)/2, py, y)
+			}
+		}
+		bw.WriteString("</g>\n")
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
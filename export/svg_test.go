@@ -0,0 +1,117 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the SVG of the universe `m` and whether it is valid XML.
+func auxSVG(m matrix.Universe, options SVGOptions) (string, error) {
+	var b bytes.Buffer
+	if err := WriteSVG(&b, m, options); err != nil {
+		return "", err
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(b.Bytes()))
+	for {
+		if _, err := decoder.Token(); err != nil {
+			if err == io.EOF {
+				return b.String(), nil
+			}
+
+			return b.String(), err
+		}
+	}
+}
+
+// Test the function WriteSVG.
+func TestWriteSVG(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := matrix.New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE)
+	m.EnablePoint(1, 2)
+	m.EnablePoint(9, 9)
+
+	svg, err := auxSVG(m, SVGOptions{ImageOptions: ImageOptions{PPP: 10}})
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">`), true, "Invalid header.")
+
+	for _, s := range []string{
+		`<rect x="0" y="0" width="100" height="100" fill="#666666"/>`,
+		`<g fill="#ffeb3b">`,
+		`<rect x="10" y="20" width="10" height="10"/>`,
+		`<rect x="90" y="90" width="10" height="10"/>`,
+	} {
+		assert.Equal(strings.Contains(svg, s), true, fmt.Sprintf("The SVG has not %s.", s))
+	}
+
+	assert.Equal(strings.Count(svg, "<rect"), 3, "Invalid number of rectangles.")
+	assert.Equal(strings.Contains(svg, "<path"), false, "The grid is drawn.")
+	assert.Equal(strings.Contains(svg, "<text"), false, "The labels are drawn.")
+}
+
+// Test the function WriteSVG with a region, the grid, the labels and the point colors.
+func TestWriteSVGOptions(t *testing.T) {
+	assert := assert.New(t)
+	m := matrix.NewSparse()
+	m.EnablePoint(-1, 0)
+	m.EnablePoint(1, 1)
+	m.EnablePoint(-1, 1)
+	m.EnablePoint(100, 100)
+
+	options := SVGOptions{
+		ImageOptions: ImageOptions{PPP: 4, Grid: true, Palette: Palette{Grid: "#fff"}},
+		Region:       &matrix.Rect{MinX: -1, MinY: 0, MaxX: 1, MaxY: 1},
+		Labels:       true,
+		LabelStep:    1,
+		PointColor: func(x, y int) string {
+			if x == 1 {
+				return "red"
+			}
+
+			if x == -1 && y == 1 {
+				return `red"/><script>alert(1)</script>`
+			}
+
+			return ""
+		},
+	}
+
+	svg, err := auxSVG(m, options)
+	assert.Equal(err, nil, "There is an error.")
+
+	for _, s := range []string{
+		`width="16" height="12" viewBox="0 0 16 12"`,
+		`<rect x="4" y="4" width="12" height="8" fill="#666666"/>`,
+		`<rect x="4" y="4" width="4" height="4"/>`,
+		`<rect x="12" y="8" width="4" height="4" fill="red"/>`,
+		`<rect x="4" y="8" width="4" height="4" fill="red&#34;/&gt;&lt;script&gt;alert(1)&lt;/script&gt;"/>`,
+		`<path stroke="#ffffff" stroke-width="1" fill="none" d="M4 4v8M8 4v8M12 4v8M16 4v8M4 4h12M4 8h12M4 12h12"/>`,
+		`<text x="6" y="2">-1</text>`,
+		`<text x="14" y="2">1</text>`,
+		`<text x="2" y="10">1</text>`,
+	} {
+		assert.Equal(strings.Contains(svg, s), true, fmt.Sprintf("The SVG has not %s.", s))
+	}
+
+	// The point outside of the region is not drawn.
+	assert.Equal(strings.Count(svg, "<rect"), 4, "Invalid number of rectangles.")
+}
+
+// Test the errors of the function WriteSVG.
+func TestWriteSVGError(t *testing.T) {
+	assert := assert.New(t)
+	m := matrix.NewSparse()
+
+	_, err := auxSVG(m, SVGOptions{ImageOptions: ImageOptions{Palette: Palette{Disabled: "grey"}}})
+	assert.Equal(err, InvalidColorError("grey"), "Invalid color error.")
+
+	_, err = auxSVG(m, SVGOptions{ImageOptions: ImageOptions{PPP: -2}})
+	assert.Equal(err, InvalidPPPError(-2, 1), "Invalid PPP error.")
+}
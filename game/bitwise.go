@@ -0,0 +1,229 @@
+package game
+
+import (
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Bit-parallel cycle of the packed matrices.
+// The 64 points of a word are processed at once: the adjacents of all of them are
+// counted using adders made with bitwise operations, so the count of each point is stored
+// in four words (bits 1, 2, 4 and 8 of the count).
+
+const wordSize int = matrix.WORD_SIZE
+
+// Returns the bit `x` of the row `row`.
+func getRowBit(row []uint64, x int) uint64 {
+	return (row[x/wordSize] >> uint(x%wordSize)) & 1
+}
+
+// Stores in `dst` the row `src` of width `width` mirrored horizontally.
+func mirrorRow(dst, src []uint64, width int) {
+	for i := range dst {
+		dst[i] = 0
+	}
+
+	for x := 0; x < width; x++ {
+		if getRowBit(src, x) == 1 {
+			m := width - 1 - x
+			dst[m/wordSize] |= uint64(1) << uint(m%wordSize)
+		}
+	}
+}
+
+// Returns the words of the row `y` of the matrix `m`. The row can be outside of the matrix:
+// the rows beyond a dead edge are empty and the rows beyond a twisted edge are mirrored.
+// `scratch` is used to store the rows that are not in the matrix.
+func getPackedRow(m *matrix.Packed, y int, scratch []uint64) []uint64 {
+	x, wy, inside := m.Wrap(0, y)
+
+	if !inside {
+		for i := range scratch {
+			scratch[i] = 0
+		}
+
+		return scratch
+	}
+
+	row, _ := m.GetRow(wy)
+	if x != 0 {
+		// The point 0 is moved to the other side: the row is mirrored.
+		mirrorRow(scratch, row, m.GetWidth())
+		return scratch
+	}
+
+	return row
+}
+
+// Returns the words with the points at the west (x - 1) and at the east (x + 1)
+// of the points of the word `i` of the row `row` of width `width`.
+// `wrapX` indicates if the left and right edges are joined.
+func shiftWord(row []uint64, i, width int, wrapX bool) (uint64, uint64) {
+	west, east := row[i]<<1, row[i]>>1
+
+	if i > 0 {
+		west |= row[i-1] >> uint(wordSize-1)
+	} else if wrapX {
+		west |= getRowBit(row, width-1)
+	}
+
+	if i < len(row)-1 {
+		east |= row[i+1] << uint(wordSize-1)
+	} else if wrapX {
+		east |= getRowBit(row, 0) << uint((width-1)%wordSize)
+	}
+
+	return west, east
+}
+
+// Adds the bits `a`, `b` and `c`. Returns the sum and the carry.
+func fullAdder(a, b, c uint64) (uint64, uint64) {
+	t := a ^ b
+	return t ^ c, (a & b) | (c & t)
+}
+
+// Adds the bits `a` and `b`. Returns the sum and the carry.
+func halfAdder(a, b uint64) (uint64, uint64) {
+	return a ^ b, a & b
+}
+
+// Counts the 8 adjacents of each point of the word `i` of the row `current`.
+// `above` and `below` are the rows around of `current`.
+// Returns the four bits of the count of each point.
+func countWordAdjacents(above, current, below []uint64, i, width int, wrapX bool) [4]uint64 {
+	aw, ae := shiftWord(above, i, width, wrapX)
+	cw, ce := shiftWord(current, i, width, wrapX)
+	bw, be := shiftWord(below, i, width, wrapX)
+
+	// Bits of weight 1.
+	s1, c1 := fullAdder(aw, above[i], ae)
+	s2, c2 := fullAdder(bw, below[i], be)
+	s3, c3 := halfAdder(cw, ce)
+	ones, c4 := fullAdder(s1, s2, s3)
+
+	// Bits of weight 2.
+	t1, d1 := fullAdder(c1, c2, c3)
+	twos, d2 := halfAdder(t1, c4)
+
+	// Bits of weight 4 and 8.
+	fours, eights := halfAdder(d1, d2)
+
+	return [4]uint64{ones, twos, fours, eights}
+}
+
+// Returns the word with the points of the word `alive` in the next cycle,
+// using the adjacents count `count` and the rule `rule`.
+func applyRuleWord(rule *Rule, alive uint64, count [4]uint64) uint64 {
+	var next uint64
+
+	for n := 0; n <= MAX_ADJACENTS; n++ {
+		if !rule.birth[n] && !rule.survival[n] {
+			continue
+		}
+
+		// Points with `n` adjacents.
+		eq := ^uint64(0)
+		for b := uint(0); b < 4; b++ {
+			if n&(1<<b) != 0 {
+				eq &= count[b]
+			} else {
+				eq &= ^count[b]
+			}
+		}
+
+		if rule.birth[n] {
+			next |= eq &^ alive
+		}
+
+		if rule.survival[n] {
+			next |= eq & alive
+		}
+	}
+
+	return next
+}
+
+// Generates the rows from `from` to `to` (not included) of the next cycle of the packed
+// matrix `m` in the matrix `next`.
+func (self *Game) stepPackedRows(m, next *matrix.Packed, from, to int) {
+	width := m.GetWidth()
+	wrapX := m.GetBoundary().WrapsX()
+	mask := m.GetLastWordMask()
+
+	current, _ := m.GetRow(0)
+	stride := len(current)
+	aboveScratch, belowScratch := make([]uint64, stride), make([]uint64, stride)
+
+	for y := from; y < to; y++ {
+		above := getPackedRow(m, y-1, aboveScratch)
+		current, _ = m.GetRow(y)
+		below := getPackedRow(m, y+1, belowScratch)
+		dst, _ := next.GetRow(y)
+
+		for i := range dst {
+			count := countWordAdjacents(above, current, below, i, width, wrapX)
+			dst[i] = applyRuleWord(self.rule, current[i], count)
+		}
+
+		// Clean the bits beyond the matrix width.
+		dst[stride-1] &= mask
+	}
+}
+
+// Generates the rows from `from` to `to` (not included) of the next cycle of the packed
+// matrix `m` in the matrix `next`, point by point. Used by the non-totalistic rules and the
+// other neighbourhoods, because the adders only count the adjacents of the Moore neighbourhood.
+func (self *Game) stepPackedRowsPoints(m, next *matrix.Packed, from, to int) {
+	width := m.GetWidth()
+
+	for y := from; y < to; y++ {
+		current, _ := m.GetRow(y)
+		dst, _ := next.GetRow(y)
+
+		for i := range dst {
+			dst[i] = 0
+		}
+
+		for x := 0; x < width; x++ {
+			enabled := getRowBit(current, x) == 1
+
+			var alive bool
+			if self.rule.IsTotalistic() {
+				alive = self.rule.NextState(enabled, self.countAdjacents(x, y))
+			} else {
+				alive = self.rule.NextStateMask(enabled, self.getAdjacentsMask(x, y))
+			}
+
+			if alive {
+				dst[x/wordSize] |= uint64(1) << uint(x%wordSize)
+			}
+		}
+	}
+}
+
+// Generates the next cycle of the packed matrix `m` in the buffer and swaps them.
+// The rows are split in strips, one per worker.
+func (self *Game) cyclePacked(m *matrix.Packed) error {
+	var err error
+	width, height := m.GetSize()
+
+	next, ok := self.buffer.(*matrix.Packed)
+	if !ok || next.GetWidth() != width || next.GetHeight() != height {
+		if next, err = matrix.NewPacked(width, height, m.GetBoundary()); err != nil {
+			return err
+		}
+
+		self.buffer = next
+	}
+
+	step := self.stepPackedRows
+	if !self.rule.IsTotalistic() || self.neighbourhood != nil {
+		step = self.stepPackedRowsPoints
+	}
+
+	runStrips(height, self.workers, func(from, to int) {
+		step(m, next, from, to)
+	})
+
+	next.Recount()
+	return m.Swap(next)
+}
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns `count` random positions inside of a matrix of size `width`x`height`.
+func auxRandomPositions(r *rand.Rand, width, height, count int) []Position {
+	positions := make([]Position, count)
+
+	for i := range positions {
+		positions[i] = Position{r.Intn(width), r.Intn(height)}
+	}
+
+	return positions
+}
+
+// Test the bit-parallel cycle generates the same points than the cycle of the dense matrix,
+// with several sizes, rules and boundary modes.
+func TestCyclePackedSameAsDense(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+	sizes := [][2]int{{min, min}, {63, 17}, {64, 12}, {65, 20}, {130, 11}}
+	rules := []string{CONWAY_RULE, "B36/S23", "B2/S", "B3678/S34678", "B0/S8", "B01245/S0123"}
+	boundaries := []matrix.Boundary{
+		matrix.BOUNDARY_DEAD,
+		matrix.BOUNDARY_TORUS,
+		matrix.BOUNDARY_KLEIN,
+		matrix.BOUNDARY_CYLINDER_X,
+		matrix.BOUNDARY_CYLINDER_Y,
+	}
+
+	for _, size := range sizes {
+		for _, rule := range rules {
+			for _, boundary := range boundaries {
+				w, h := size[0], size[1]
+				positions := auxRandomPositions(r, w, h, w*h/3)
+				options := Options{Rule: MustParseRule(rule), Boundary: boundary}
+
+				options.Storage = matrix.STORAGE_DENSE
+				dense, _ := NewWithOptions(w, h, positions, options)
+				options.Storage = matrix.STORAGE_PACKED
+				packed, _ := NewWithOptions(w, h, positions, options)
+
+				for i := 0; i < 8; i++ {
+					assert.Equal(dense.Cycle(), nil, "There is an error.")
+					assert.Equal(packed.Cycle(), nil, "There is an error.")
+
+					message := fmt.Sprintf("Invalid cycle %d (%dx%d, %s, %s).", i+1, w, h, rule, boundary)
+					assert.Equal(auxEnabledPositions(packed), auxEnabledPositions(dense), message)
+					assert.Equal(packed.matrix.GetPointsEnabled(), dense.matrix.GetPointsEnabled(), message)
+				}
+			}
+		}
+	}
+}
+
+func benchmarkCycle(b *testing.B, storage matrix.Storage) {
+	r := rand.New(rand.NewSource(1))
+	options := Options{Boundary: matrix.BOUNDARY_TORUS, Storage: storage}
+	g, _ := NewWithOptions(256, 256, auxRandomPositions(r, 256, 256, 256*256/3), options)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		g.Cycle()
+	}
+}
+
+func BenchmarkCycleDense(b *testing.B)  { benchmarkCycle(b, matrix.STORAGE_DENSE) }
+func BenchmarkCyclePacked(b *testing.B) { benchmarkCycle(b, matrix.STORAGE_PACKED) }
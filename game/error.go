@@ -0,0 +1,43 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+type invalidRuleError struct {
+	rule   string
+	reason string
+}
+
+func (e *invalidRuleError) Error() string {
+	message := "The rule \"%s\" is invalid: %s."
+	return fmt.Sprintf(message, e.rule, e.reason)
+}
+
+func InvalidRuleError(rule, reason string) error {
+	return &invalidRuleError{rule, reason}
+}
+
+type invalidNeighbourhoodError string
+
+func (e *invalidNeighbourhoodError) Error() string {
+	return fmt.Sprintf("The neighbourhood is invalid: %s.", string(*e))
+}
+
+func InvalidNeighbourhoodError(reason string) error {
+	err := invalidNeighbourhoodError(reason)
+	return &err
+}
+
+type unsupportedUniverseError string
+
+func (e *unsupportedUniverseError) Error() string {
+	return fmt.Sprintf("The universe %s is not supported.", string(*e))
+}
+
+func UnsupportedUniverseError(u matrix.Universe) error {
+	err := unsupportedUniverseError(fmt.Sprintf("%T", u))
+	return &err
+}
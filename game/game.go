@@ -5,25 +5,135 @@ import (
 )
 
 type Game struct {
-	matrix *matrix.Matrix
+	matrix matrix.Universe
 	cycles uint
+	rule   *Rule
+
+	// Matrix where the next cycle is generated before swapping it with the game matrix.
+	buffer matrix.Universe
+
+	// Number of goroutines used to generate the cycles.
+	workers int
+
+	// Hashes of the states used to detect the period. Nil when it is not detected.
+	detector *periodDetector
+
+	// Summed-area table used to count the adjacents in the Larger than Life rules.
+	table summedAreaTable
+
+	// Adjacents of the points. Nil with the Moore neighbourhood.
+	neighbourhood *Neighbourhood
+
+	// Shape of the cells of the matrix. The other topologies than the square one use their
+	// own adjacents.
+	topology matrix.Topology
 }
 
 type Position [2]int
 
+// Settings used to make a new game.
+type Options struct {
+	// Rule used in the game cycles. The Conway's rule is used when it is nil.
+	Rule *Rule
+
+	// Boundary mode of the game matrix.
+	Boundary matrix.Boundary
+
+	// Storage of the game points. With unbounded storages the game size is ignored.
+	// The default storage is the packed matrix, that uses the bit-parallel cycle, or the dense
+	// matrix with the Generations and the Larger than Life rules.
+	Storage matrix.Storage
+
+	// Number of goroutines used to generate the cycles of the bounded storages.
+	// Each one generates a strip of the matrix. Zero or one run the cycles serially.
+	Workers int
+
+	// Whether saves the hash of the state of each cycle to detect the period of the game,
+	// and the displacement of the spaceships.
+	DetectPeriod bool
+
+	// Whether `Run` stops once the period is found. It enables `DetectPeriod`.
+	StopOnPeriod bool
+
+	// Adjacents of the points. The Moore neighbourhood is used when it is nil.
+	// The non-totalistic and the Larger than Life rules only use their own neighbourhoods.
+	Neighbourhood *Neighbourhood
+
+	// Shape of the cells of the game matrix. The hexagonal and the triangular topologies need
+	// the dense storage and a totalistic rule, and they can not use other neighbourhoods.
+	Topology matrix.Topology
+}
+
 // Make new game with a matrix of size `width`x`height`
 // Param `position` allow define the initial cells enabled in the matrix.
 // returns the type Game or an error.
 func New(width, height int, positions []Position) (*Game, error) {
+	return NewWithOptions(width, height, positions, Options{})
+}
+
+// Same as `New` but the game cycles use the rule `rule` instead of the Conway's rule.
+func NewWithRule(width, height int, positions []Position, rule *Rule) (*Game, error) {
+	return NewWithOptions(width, height, positions, Options{Rule: rule})
+}
+
+// Same as `New` but the game is made using the settings `options`.
+func NewWithOptions(width, height int, positions []Position, options Options) (*Game, error) {
 	var err error = nil
-	m, err := matrix.New(width, height)
+
+	rule := options.Rule
+	if rule == nil {
+		rule = MustParseRule(CONWAY_RULE)
+	}
+
+	storage := options.Storage
+	if storage == matrix.STORAGE_DEFAULT && (rule.GetStates() > 2 || rule.IsLargerThanLife()) {
+		storage = matrix.STORAGE_DENSE
+	}
+
+	m, err := matrix.NewUniverseWithTopology(storage, width, height, options.Boundary, options.Topology)
 
 	// Check if the matrix has an error.
 	if err != nil {
 		return nil, err
 	}
 
-	g := &Game{m, 0}
+	if !m.IsBounded() && rule.IsBirth(0) {
+		// The points without adjacents would get enabled in the infinite universe.
+		return nil, InvalidRuleError(rule.String(), "the B0 rules need a bounded storage")
+	}
+
+	if _, dense := m.(*matrix.Matrix); !dense && rule.GetStates() > 2 {
+		// Only the dense matrix stores the dying states.
+		return nil, InvalidRuleError(rule.String(), "the Generations rules need the dense storage")
+	}
+
+	if _, dense := m.(*matrix.Matrix); !dense && rule.IsLargerThanLife() {
+		// The adjacents are counted using the columns of the dense matrix.
+		return nil, InvalidRuleError(rule.String(), "the Larger than Life rules need the dense storage")
+	}
+
+	if n := options.Neighbourhood; n != nil && !n.IsMoore() && (!rule.IsTotalistic() || rule.IsLargerThanLife()) {
+		return nil, InvalidRuleError(rule.String(), "the rule can not use other neighbourhoods")
+	}
+
+	if options.Topology != matrix.TOPOLOGY_SQUARE {
+		if !rule.IsTotalistic() || rule.IsLargerThanLife() {
+			return nil, InvalidRuleError(rule.String(), "the rule can not use other topologies")
+		}
+
+		if n := options.Neighbourhood; n != nil && !n.IsMoore() {
+			return nil, InvalidNeighbourhoodError("the topologies only use their own adjacents")
+		}
+	}
+
+	g := &Game{matrix: m, rule: rule, workers: options.Workers, topology: options.Topology}
+	if n := options.Neighbourhood; n != nil && !n.IsMoore() {
+		g.neighbourhood = n
+	}
+
+	if options.DetectPeriod || options.StopOnPeriod {
+		g.detector = &periodDetector{history: map[uint64][]stateRecord{}, stopOnPeriod: options.StopOnPeriod}
+	}
 
 	// Enable the initial positions.
 	for _, position := range positions {
@@ -35,100 +145,240 @@ func New(width, height int, positions []Position) (*Game, error) {
 		return g, err
 	}
 
+	g.recordState()
 	return g, nil
 }
 
 // Returns the numbers of point enabled of the matrix `self.matrix` around of the point `x`, `y`
+// The points beyond the matrix edges are got using the matrix boundary mode.
+// With other neighbourhoods, returns the sum of the weights of the adjacents enabled, and with
+// other topologies, the adjacents of the topology enabled.
 func (self *Game) countAdjacents(x, y int) int {
-	var xmin, xmax, ymin, ymax, count int
-	width, height := self.matrix.GetSize()
-	increment := map[bool]int{false: 0, true: 1}
-
-	// Define the adjacent zone to the point x,y
-	if xmin = x; x > 0 {
-		xmin = x - 1
+	if self.neighbourhood != nil {
+		return self.countNeighbourhood(x, y)
 	}
 
-	if xmax = x; x < width-1 {
-		xmax = x + 1
+	if self.topology != matrix.TOPOLOGY_SQUARE {
+		return self.countTopology(x, y)
 	}
 
-	if ymin = y; y > 0 {
-		ymin = y - 1
-	}
+	count := 0
 
-	if ymax = y; y < height-1 {
-		ymax = y + 1
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			if i == 0 && j == 0 {
+				continue
+			}
+
+			ax, ay, inside := self.matrix.Wrap(x+i, y+j)
+			if !inside {
+				continue
+			}
+
+			if enabled, _ := self.matrix.IsEnabled(ax, ay); enabled {
+				count++
+			}
+		}
 	}
 
-	count = 0
+	return count
+}
+
+// Returns the sum of the weights of the adjacents enabled of the point `x`, `y` in the
+// neighbourhood of the game.
+func (self *Game) countNeighbourhood(x, y int) int {
+	count := 0
+
+	for _, a := range self.neighbourhood.adjacents {
+		ax, ay, inside := self.matrix.Wrap(x+a.Dx, y+a.Dy)
+		if !inside {
+			continue
+		}
 
-	// Count adjacents.
-	for i := xmin; i <= xmax; i++ {
-		for j := ymin; j <= ymax; j++ {
-			enabled, _ := self.matrix.IsEnabled(i, j)
-			count += increment[enabled]
+		if enabled, _ := self.matrix.IsEnabled(ax, ay); enabled {
+			count += a.Weight
 		}
 	}
 
-	// Decrement the point x, y whether it is enabled.
-	enabled, _ := self.matrix.IsEnabled(x, y)
-	count -= increment[enabled]
+	return count
+}
+
+// Returns the number of adjacents enabled of the point `x`, `y` in the topology of the game.
+func (self *Game) countTopology(x, y int) int {
+	count := 0
+
+	for _, offset := range self.topology.GetAdjacentOffsets(x, y) {
+		ax, ay, inside := self.matrix.Wrap(x+offset[0], y+offset[1])
+		if !inside {
+			continue
+		}
+
+		if enabled, _ := self.matrix.IsEnabled(ax, ay); enabled {
+			count++
+		}
+	}
 
 	return count
 }
 
+// Returns the mask with the adjacents enabled of the point `x`, `y`, used by the
+// non-totalistic rules. The points beyond the matrix edges are got using the matrix boundary mode.
+func (self *Game) getAdjacentsMask(x, y int) uint8 {
+	return GetAdjacentsMask(func(dx, dy int) bool {
+		ax, ay, inside := self.matrix.Wrap(x+dx, y+dy)
+		if !inside {
+			return false
+		}
+
+		enabled, _ := self.matrix.IsEnabled(ax, ay)
+		return enabled
+	})
+}
+
+// Returns the points that can change in the next cycle of an unbounded matrix:
+// the points enabled and the points that have them as adjacents.
+func (self *Game) getCandidatePoints() []Position {
+	candidates := map[Position]bool{}
+
+	self.matrix.ForEachEnabled(func(x, y int) {
+		if self.neighbourhood != nil {
+			candidates[Position{x, y}] = true
+			for _, a := range self.neighbourhood.adjacents {
+				candidates[Position{x - a.Dx, y - a.Dy}] = true
+			}
+
+			return
+		}
+
+		for i := -1; i <= 1; i++ {
+			for j := -1; j <= 1; j++ {
+				candidates[Position{x + i, y + j}] = true
+			}
+		}
+	})
+
+	points := make([]Position, 0, len(candidates))
+	for p := range candidates {
+		points = append(points, p)
+	}
+
+	return points
+}
+
 // Returns the mtrix game.
-func (self *Game) GetMatrix() *matrix.Matrix {
+func (self *Game) GetMatrix() matrix.Universe {
 	return self.matrix
 }
 
-// Using the game rules, modify status of the point `x`, `y`, depending of the param `enabled`,
-// that indicates if the point is enabled, and the  param `adj` that is the number of enabled
-// points adjacents. The **out** param `error` is used for returns the errors.
-func (self *Game) rules(enabled bool, adj, x, y int, err *error) {
-	if *err != nil {
-		// There is an error from parent.
-		return
+// Using the game rules, sets in the universe `u` the status of the point `x`, `y` in the next
+// cycle, depending of the param `enabled`, that indicates if the point is enabled, and the
+// param `adj` that is the number of enabled points adjacents.
+// Returns the error of the universe.
+func (self *Game) rules(u matrix.Universe, enabled bool, adj, x, y int) error {
+	if self.rule.NextState(enabled, adj) {
+		return u.EnablePoint(x, y)
 	}
 
-	// Apply the game rules depending of `adj` and `enabled`
-	// When point is enabled
-	if enabled {
-		if adj != 2 && adj != 3 {
-			*err = self.matrix.DisablePoint(x, y)
-			return
+	return u.DisablePoint(x, y)
+}
+
+// Same as `rules` but using the mask `mask` with the adjacents enabled, for the non-totalistic
+// rules.
+func (self *Game) rulesMask(u matrix.Universe, enabled bool, mask uint8, x, y int) error {
+	if self.rule.NextStateMask(enabled, mask) {
+		return u.EnablePoint(x, y)
+	}
+
+	return u.DisablePoint(x, y)
+}
+
+// Generates the next cycle of the sparse universe `m` in the buffer and swaps them.
+// Only the points enabled and their adjacents are checked.
+func (self *Game) cycleSparse(m *matrix.Sparse) error {
+	next, ok := self.buffer.(*matrix.Sparse)
+	if !ok {
+		next = matrix.NewSparse()
+		self.buffer = next
+	}
+
+	next.Reset()
+
+	for _, p := range self.getCandidatePoints() {
+		enabled, err := m.IsEnabled(p[0], p[1])
+		if err != nil {
+			return err
+		}
+
+		if self.rule.IsTotalistic() {
+			err = self.rules(next, enabled, self.countAdjacents(p[0], p[1]), p[0], p[1])
+		} else {
+			err = self.rulesMask(next, enabled, self.getAdjacentsMask(p[0], p[1]), p[0], p[1])
+		}
+
+		if err != nil {
+			return err
 		}
-	} else if adj == 3 {
-		*err = self.matrix.EnablePoint(x, y)
-		return
 	}
 
-	*err = nil
+	m.Swap(next)
+	return nil
 }
 
 // The func run all points in matrix, apply the rules in they
 // and generate a new state of the matrix.
-func (self *Game) Cycle() (err error) {
-	err = nil
-	width, height := self.matrix.GetWidth(), self.matrix.GetHeight()
-
-	for i := 0; i < width; i++ {
-		for j := 0; j < height; j++ {
-			enabled, err := self.matrix.IsEnabled(i, j)
+// The new state is generated in a second matrix, the buffer, that is swapped with the game
+// matrix at the end, so the points are not modified until all of them are checked.
+// In the unbounded matrices only the points enabled and their adjacents are checked.
+func (self *Game) Cycle() error {
+	var err error
 
-			if err != nil {
-				return err
-			}
+	switch m := self.matrix.(type) {
+	case *matrix.Packed:
+		err = self.cyclePacked(m)
+	case *matrix.Matrix:
+		err = self.cycleDense(m)
+	case *matrix.Sparse:
+		err = self.cycleSparse(m)
+	default:
+		err = UnsupportedUniverseError(m)
+	}
 
-			adj := self.countAdjacents(i, j)
-			// Using deffer check the rules and apply the new state of the point.
-			defer self.rules(enabled, adj, i, j, &err)
-		}
+	if err != nil {
+		return err
 	}
 
 	self.cycles++
-	return
+	self.recordState()
+	return nil
+}
+
+// Returns the neighbourhood of the points of the game.
+func (self *Game) GetNeighbourhood() *Neighbourhood {
+	if self.neighbourhood == nil {
+		return NewMooreNeighbourhood()
+	}
+
+	return self.neighbourhood
+}
+
+// Returns the topology of the game matrix.
+func (self *Game) GetTopology() matrix.Topology {
+	return self.topology
+}
+
+// Returns the rule used in the game cycles.
+func (self *Game) GetRule() *Rule {
+	return self.rule
+}
+
+// Sets the number of goroutines used to generate the cycles.
+func (self *Game) SetWorkers(workers int) {
+	self.workers = workers
+}
+
+// Returns the number of goroutines used to generate the cycles.
+func (self *Game) GetWorkers() int {
+	return self.workers
 }
 
 // Get the number of cycles.
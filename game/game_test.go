@@ -295,7 +295,7 @@ func TestRule1InFuncRule(t *testing.T) {
 	g, _ := New(min, min, []Position{{0, 0}, {1, 1}, {2, 2}})
 
 	adj := g.countAdjacents(1, 1)
-	g.rules(true, adj, 1, 1, &err)
+	err = g.rules(g.matrix, true, adj, 1, 1)
 	enabled, _ := g.GetMatrix().IsEnabled(1, 1)
 	assert.Equal(err, nil, "There is an error.j")
 	assert.Equal(enabled, true, "The point 1x1 is disabled.")
@@ -303,7 +303,7 @@ func TestRule1InFuncRule(t *testing.T) {
 	// 3 cells adjacents
 	g, _ = New(min, min, []Position{{0, 0}, {1, 1}, {2, 1}, {2, 2}})
 	adj = g.countAdjacents(1, 1)
-	g.rules(true, adj, 1, 1, &err)
+	err = g.rules(g.matrix, true, adj, 1, 1)
 	enabled, _ = g.GetMatrix().IsEnabled(1, 1)
 	assert.Equal(err, nil, "There is an error.j")
 	assert.Equal(enabled, true, "The point 1x1 is disabled.")
@@ -317,7 +317,7 @@ func TestRule2InFuncRule(t *testing.T) {
 	// 1 live cell adjacents.
 	g, _ := New(min, min, []Position{{0, 0}, {1, 1}})
 	adj := g.countAdjacents(1, 1)
-	g.rules(true, adj, 1, 1, &err)
+	err = g.rules(g.matrix, true, adj, 1, 1)
 	enabled, _ := g.GetMatrix().IsEnabled(1, 1)
 	assert.Equal(err, nil, "There is an error.")
 	assert.Equal(enabled, false, "The point is enabled.")
@@ -325,7 +325,7 @@ func TestRule2InFuncRule(t *testing.T) {
 	// 4 or more cells lives.
 	g, _ = New(min, min, []Position{{0, 0}, {1, 1}, {1, 0}, {0, 1}, {1, 2}})
 	adj = g.countAdjacents(1, 1)
-	g.rules(true, adj, 1, 1, &err)
+	err = g.rules(g.matrix, true, adj, 1, 1)
 	enabled, _ = g.GetMatrix().IsEnabled(1, 1)
 	assert.Equal(err, nil, "There is an error.")
 	assert.Equal(enabled, false, "The point is enabled.")
@@ -339,7 +339,7 @@ func TestRule3InFuncRule(t *testing.T) {
 	// 1 live cell adjacents.
 	g, _ := New(min, min, []Position{{0, 0}})
 	adj := g.countAdjacents(1, 1)
-	g.rules(false, adj, 1, 1, &err)
+	err = g.rules(g.matrix, false, adj, 1, 1)
 	enabled, _ := g.GetMatrix().IsEnabled(1, 1)
 	assert.Equal(err, nil, "There is an error.")
 	assert.Equal(enabled, false, "The point is enabled.")
@@ -347,7 +347,7 @@ func TestRule3InFuncRule(t *testing.T) {
 	// 4 live cells adjacents.
 	g, _ = New(min, min, []Position{{0, 0}, {0, 1}, {0, 2}, {1, 0}})
 	adj = g.countAdjacents(1, 1)
-	g.rules(false, adj, 1, 1, &err)
+	err = g.rules(g.matrix, false, adj, 1, 1)
 	enabled, _ = g.GetMatrix().IsEnabled(1, 1)
 	assert.Equal(err, nil, "There is an error.")
 	assert.Equal(enabled, false, "The point is enabled.")
@@ -361,7 +361,7 @@ func TestRule4InFuncRule(t *testing.T) {
 	// 3 live cells adjacents.
 	g, _ := New(min, min, []Position{{0, 0}, {0, 1}, {0, 2}})
 	adj := g.countAdjacents(1, 1)
-	g.rules(false, adj, 1, 1, &err)
+	err = g.rules(g.matrix, false, adj, 1, 1)
 	enabled, _ := g.GetMatrix().IsEnabled(1, 1)
 	assert.Equal(err, nil, "There is an error.")
 	assert.Equal(enabled, true, "The point is disabled.")
@@ -373,7 +373,7 @@ func TestErrorInFuncrule(t *testing.T) {
 	assert := assert.New(t)
 
 	g, _ := New(min, min, []Position{})
-	g.rules(true, 0, 11, 11, &err)
+	err = g.rules(g.matrix, true, 0, 11, 11)
 	assert.Equal(err, matrix.OutIndexError(g.matrix, 11, 11), "The error does not match.")
 }
 
@@ -431,3 +431,304 @@ func TestFuncGetCyclesNum(t *testing.T) {
 		assert.Equal(g.GetCyclesNum(), uint(i), "Invalid number of cyles.")
 	}
 }
+
+// Returns the positions enabled in the game matrix.
+func auxEnabledPositions(g *Game) map[Position]bool {
+	positions := map[Position]bool{}
+	g.matrix.ForEachEnabled(func(x, y int) {
+		positions[Position{x, y}] = true
+	})
+
+	return positions
+}
+
+// Test the function countAdjacents when the points are beyond the matrix edges.
+func TestCountAdjacentsBoundary(t *testing.T) {
+	assert := assert.New(t)
+	options := Options{Boundary: matrix.BOUNDARY_TORUS}
+	g, _ := NewWithOptions(min, min, []Position{{9, 9}, {0, 9}, {9, 0}, {5, 5}}, options)
+	assert.Equal(g.countAdjacents(0, 0), 3, "Invalid adjacents in the torus.")
+
+	options.Boundary = matrix.BOUNDARY_KLEIN
+	g, _ = NewWithOptions(min, min, []Position{{7, 9}, {2, 9}}, options)
+	assert.Equal(g.countAdjacents(2, 0), 1, "Invalid adjacents in the Klein bottle.")
+
+	options.Boundary = matrix.BOUNDARY_CYLINDER_X
+	g, _ = NewWithOptions(min, min, []Position{{9, 5}, {0, 9}}, options)
+	assert.Equal(g.countAdjacents(0, 5), 1, "Invalid adjacents in the horizontal cylinder.")
+	assert.Equal(g.countAdjacents(0, 0), 0, "Invalid adjacents in the horizontal cylinder.")
+
+	options.Boundary = matrix.BOUNDARY_CYLINDER_Y
+	g, _ = NewWithOptions(min, min, []Position{{9, 5}, {0, 9}}, options)
+	assert.Equal(g.countAdjacents(0, 5), 0, "Invalid adjacents in the vertical cylinder.")
+	assert.Equal(g.countAdjacents(0, 0), 1, "Invalid adjacents in the vertical cylinder.")
+}
+
+// Test the spaceships come back to the initial position in a torus.
+func TestCycleFuncTorusSpaceships(t *testing.T) {
+	assert := assert.New(t)
+	glider := []Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	lwss := []Position{{1, 0}, {4, 0}, {0, 1}, {0, 2}, {4, 2}, {0, 3}, {1, 3}, {2, 3}, {3, 3}}
+	tests := []struct {
+		name          string
+		width, height int
+		positions     []Position
+		cycles        int
+	}{
+		// The glider moves 1 point in diagonal each 4 cycles.
+		{"glider", min, min, glider, 4 * min},
+		{"glider", 12, 18, glider, 4 * 36},
+		// The lightweight spaceship moves 2 points in horizontal each 4 cycles.
+		{"lwss", 2 * min, min, lwss, 2 * 2 * min},
+	}
+
+	for _, test := range tests {
+		for _, storage := range []matrix.Storage{matrix.STORAGE_DENSE, matrix.STORAGE_PACKED} {
+			options := Options{Boundary: matrix.BOUNDARY_TORUS, Storage: storage}
+			g, _ := NewWithOptions(test.width, test.height, test.positions, options)
+			initial := auxEnabledPositions(g)
+
+			for i := 0; i < test.cycles; i++ {
+				err := g.Cycle()
+				assert.Equal(err, nil, "There is an error.")
+
+				if i < test.cycles-1 {
+					message := "The %s returns in the cycle %d (%s)."
+					assert.NotEqual(auxEnabledPositions(g), initial, fmt.Sprintf(message, test.name, i+1, storage))
+				}
+			}
+
+			message := "The %s does not come back (%s)."
+			assert.Equal(auxEnabledPositions(g), initial, fmt.Sprintf(message, test.name, storage))
+		}
+	}
+}
+
+// Test the glider does not come back to the initial position with dead edges.
+func TestCycleFuncDeadEdgeGlider(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := New(min, min, []Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}})
+	initial := auxEnabledPositions(g)
+
+	for i := 0; i < 4*min; i++ {
+		g.Cycle()
+	}
+
+	assert.NotEqual(auxEnabledPositions(g), initial, "The glider comes back.")
+}
+
+// Test the glider travels without limit in the sparse storage.
+func TestCycleFuncSparseGlider(t *testing.T) {
+	assert := assert.New(t)
+	// Glider moving to left-top.
+	glider := []Position{{1, 2}, {0, 1}, {2, 0}, {1, 0}, {0, 0}}
+	options := Options{Storage: matrix.STORAGE_SPARSE}
+	g, err := NewWithOptions(0, 0, glider, options)
+	assert.Equal(err, nil, "There is an error.")
+
+	for i := 0; i < 4*25; i++ {
+		assert.Equal(g.Cycle(), nil, "There is an error.")
+	}
+
+	assert.Equal(g.matrix.GetPointsEnabled(), len(glider), "Invalid points enabled.")
+	for _, p := range glider {
+		enabled, _ := g.matrix.IsEnabled(p[0]-25, p[1]-25)
+		assert.Equal(enabled, true, fmt.Sprintf("The point %dx%d is disabled.", p[0]-25, p[1]-25))
+	}
+
+	box, _ := g.matrix.GetBoundingBox()
+	assert.Equal(box, matrix.Rect{MinX: -25, MinY: -25, MaxX: -23, MaxY: -23}, "Invalid bounding box.")
+}
+
+// Test the sparse and dense storages generate the same cycles.
+func TestCycleFuncSparseDense(t *testing.T) {
+	assert := assert.New(t)
+	// R-pentomino in the center of the matrix.
+	rpentomino := []Position{{31, 30}, {32, 30}, {30, 31}, {31, 31}, {31, 32}}
+	dense, _ := NewWithOptions(64, 64, rpentomino, Options{Storage: matrix.STORAGE_DENSE})
+	sparse, _ := NewWithOptions(0, 0, rpentomino, Options{Storage: matrix.STORAGE_SPARSE})
+
+	for i := 0; i < 20; i++ {
+		dense.Cycle()
+		sparse.Cycle()
+
+		positions := map[Position]bool{}
+		sparse.matrix.ForEachEnabled(func(x, y int) {
+			positions[Position{x, y}] = true
+		})
+
+		assert.Equal(positions, auxEnabledPositions(dense), fmt.Sprintf("Invalid cycle %d.", i+1))
+	}
+}
+
+// Test the B0 rules are not allowed in the sparse storage.
+func TestNewGameSparseB0Error(t *testing.T) {
+	assert := assert.New(t)
+	options := Options{Rule: MustParseRule("B0/S8"), Storage: matrix.STORAGE_SPARSE}
+	_, err := NewWithOptions(0, 0, []Position{}, options)
+	assert.Equal(err, InvalidRuleError("B0/S8", "the B0 rules need a bounded storage"), "The error does not match.")
+}
+
+// Test the cycles of boards with millions of points. The deferred mutation of the points
+// made the stack grow with the board size.
+func TestCycleFuncLargeBoard(t *testing.T) {
+	assert := assert.New(t)
+	w, h := 1200, 1000
+	// Blinkers in all the board.
+	positions := []Position{}
+	for x := 1; x < w-3; x += 5 {
+		for y := 1; y < h-3; y += 5 {
+			positions = append(positions, Position{x, y}, Position{x + 1, y}, Position{x + 2, y})
+		}
+	}
+
+	for _, storage := range []matrix.Storage{matrix.STORAGE_DENSE, matrix.STORAGE_SPARSE} {
+		g, err := NewWithOptions(w, h, positions, Options{Storage: storage})
+		assert.Equal(err, nil, "There is an error.")
+
+		err = g.Cycle()
+		assert.Equal(err, nil, "There is an error.")
+		assert.Equal(g.matrix.GetPointsEnabled(), len(positions), "Invalid points enabled.")
+
+		// The horizontal blinkers are vertical now.
+		enabled, _ := g.matrix.IsEnabled(2, 0)
+		assert.Equal(enabled, true, "The point 2x0 is disabled.")
+		enabled, _ = g.matrix.IsEnabled(1, 1)
+		assert.Equal(enabled, false, "The point 1x1 is enabled.")
+
+		err = g.Cycle()
+		assert.Equal(err, nil, "There is an error.")
+		for _, p := range positions {
+			if enabled, _ := g.matrix.IsEnabled(p[0], p[1]); !enabled {
+				assert.Fail(fmt.Sprintf("The point %dx%d is disabled (%s).", p[0], p[1], storage))
+				break
+			}
+		}
+	}
+}
+
+// Test the game matrix is not modified until all the points are checked.
+func TestCycleFuncDoubleBuffer(t *testing.T) {
+	assert := assert.New(t)
+	// Block: every point depends on points checked before and after it.
+	block := []Position{{4, 4}, {5, 4}, {4, 5}, {5, 5}}
+
+	for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE, matrix.STORAGE_SPARSE} {
+		g, _ := NewWithOptions(min, min, block, Options{Storage: storage})
+		m := g.GetMatrix()
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(g.Cycle(), nil, "There is an error.")
+		}
+
+		// The matrix returned before the cycles has the current points.
+		assert.Equal(m.GetPointsEnabled(), len(block), fmt.Sprintf("Invalid points enabled (%s).", storage))
+		for _, p := range block {
+			enabled, _ := m.IsEnabled(p[0], p[1])
+			assert.Equal(enabled, true, fmt.Sprintf("The point %dx%d is disabled (%s).", p[0], p[1], storage))
+		}
+	}
+}
+
+// Universe of other package, unknown by the game.
+type auxUniverse struct {
+	matrix.Universe
+}
+
+// Test the function `game.Cycle` returns an error with the unknown universes.
+func TestCycleFuncUnknownUniverse(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := New(min, min, []Position{{4, 4}, {5, 4}, {6, 4}})
+	g.matrix = auxUniverse{g.matrix}
+
+	err := g.Cycle()
+	assert.Equal(err, UnsupportedUniverseError(g.matrix), "The error does not match.")
+	assert.Equal(err.Error(), "The universe game.auxUniverse is not supported.", "Invalid error message.")
+	assert.Equal(g.GetCyclesNum(), uint(0), "The cycle is counted.")
+}
+
+// Test the rule B1/S in the other topologies: a point enables all its adjacents.
+func TestCycleFuncTopologies(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[matrix.Topology]map[Position]bool{
+		matrix.TOPOLOGY_HEXAGONAL: {
+			{5, 4}: true, {6, 4}: true, {4, 5}: true, {6, 5}: true, {4, 6}: true, {5, 6}: true,
+		},
+		// The triangle 5x5 points up: it touches 3 triangles above and 5 below.
+		matrix.TOPOLOGY_TRIANGULAR: {
+			{4, 4}: true, {5, 4}: true, {6, 4}: true,
+			{3, 5}: true, {4, 5}: true, {6, 5}: true, {7, 5}: true,
+			{3, 6}: true, {4, 6}: true, {5, 6}: true, {6, 6}: true, {7, 6}: true,
+		},
+	}
+
+	for topology, expected := range tests {
+		options := Options{Rule: MustParseRule("B1/S"), Storage: matrix.STORAGE_DENSE, Topology: topology}
+		g, err := NewWithOptions(min, min, []Position{{5, 5}}, options)
+		assert.Equal(err, nil, fmt.Sprintf("There is an error with the topology %s.", topology))
+		assert.Equal(g.GetTopology(), topology, "Invalid topology of the game.")
+
+		g.Cycle()
+		assert.Equal(auxEnabledPositions(g), expected, fmt.Sprintf("Invalid points with the topology %s.", topology))
+	}
+}
+
+// Test the hexagonal topology is the hexagonal neighbourhood emulated in the square grid,
+// mirrored horizontally.
+func TestCycleFuncHexagonalMirror(t *testing.T) {
+	assert := assert.New(t)
+	rule := MustParseRule("B2/S34")
+	n, _ := ParseNeighbourhood("hexagonal")
+
+	soup, mirrored := []Position{}, []Position{}
+	for i := 0; i < 256; i++ {
+		if (i*i*7+i*13)%5 < 2 {
+			soup = append(soup, Position{24 + i%16, 24 + i/16})
+			mirrored = append(mirrored, Position{63 - 24 - i%16, 24 + i/16})
+		}
+	}
+
+	options := Options{Rule: rule, Storage: matrix.STORAGE_DENSE, Boundary: matrix.BOUNDARY_TORUS}
+	emulated, _ := NewWithOptions(64, 64, mirrored, options)
+	options.Topology = matrix.TOPOLOGY_HEXAGONAL
+	hexagonal, _ := NewWithOptions(64, 64, soup, options)
+	emulated.neighbourhood = n
+
+	for i := 0; i < 20; i++ {
+		hexagonal.Cycle()
+		emulated.Cycle()
+
+		positions := map[Position]bool{}
+		for p := range auxEnabledPositions(emulated) {
+			positions[Position{63 - p[0], p[1]}] = true
+		}
+
+		assert.Equal(auxEnabledPositions(hexagonal), positions, fmt.Sprintf("Invalid cycle %d.", i+1))
+	}
+}
+
+// Test the errors of the games with other topologies.
+func TestNewGameTopologyError(t *testing.T) {
+	assert := assert.New(t)
+	hexagonal := matrix.TOPOLOGY_HEXAGONAL
+
+	_, err := NewWithOptions(min, min, nil, Options{Topology: hexagonal})
+	assert.Equal(err, matrix.TopologyError(hexagonal, "it needs the dense storage"), "The error of the storage does not match.")
+
+	for _, rulestring := range []string{"B2-a/S12", "R2,C0,M0,S2..3,B3..3,NM"} {
+		rule := MustParseRule(rulestring)
+		_, err = NewWithOptions(min, min, nil, Options{Rule: rule, Storage: matrix.STORAGE_DENSE, Topology: hexagonal})
+		reason := "the rule can not use other topologies"
+		assert.Equal(err, InvalidRuleError(rule.String(), reason), fmt.Sprintf("The error of %s does not match.", rulestring))
+	}
+
+	n, _ := ParseNeighbourhood("vonneumann")
+	_, err = NewWithOptions(min, min, nil, Options{Storage: matrix.STORAGE_DENSE, Topology: hexagonal, Neighbourhood: n})
+	reason := "the topologies only use their own adjacents"
+	assert.Equal(err, InvalidNeighbourhoodError(reason), "The error of the neighbourhood does not match.")
+
+	// The Generations rules use the adjacents of the topology.
+	options := Options{Rule: MustParseRule("B2/S/C3"), Storage: matrix.STORAGE_DENSE, Topology: matrix.TOPOLOGY_TRIANGULAR}
+	_, err = NewWithOptions(min, min, nil, options)
+	assert.Equal(err, nil, "There is an error with the Generations rule.")
+}
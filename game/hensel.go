@@ -0,0 +1,202 @@
+package game
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// Isotropic non-totalistic rules, in the Hensel notation: the digits of the rulestring can
+// have letters with the configurations of the adjacents where the rule is applied, or a '-'
+// and the letters of the configurations where it is not applied. Example: "B2-a/S12".
+// The configurations of the adjacents are masks of 8 bits, one bit per adjacent, and the
+// configurations that are the same after rotating or reflecting them have the same letter.
+
+// Positions of the adjacents of a point, relative to it, in the bits of the masks.
+var adjacentOffsets [MAX_ADJACENTS]Position = [MAX_ADJACENTS]Position{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-1, 0}, {1, 0},
+	{-1, 1}, {0, 1}, {1, 1},
+}
+
+// Letters of the configurations of each number of adjacents, in canonical order.
+// The numbers 5 to 8 use the letters of 3 to 0: their configurations are the complements.
+var henselLetters [MAX_ADJACENTS + 1]string = [MAX_ADJACENTS + 1]string{
+	"", "ce", "ceaikn", "ceaiknjqry", "ceaiknjqrytwz", "ceaiknjqry", "ceaikn", "ce", "",
+}
+
+// A configuration of each letter of `henselLetters`, from 1 to 4 adjacents.
+var henselMasks [5][]uint8 = [5][]uint8{
+	{},
+	{0x01, 0x02},
+	{0x05, 0x0a, 0x03, 0x18, 0x11, 0x24},
+	{0x25, 0x1a, 0x0b, 0x07, 0x32, 0x0d, 0x0e, 0x26, 0x19, 0x31},
+	{0xa5, 0x5a, 0x0f, 0x1d, 0x33, 0x27, 0x3a, 0x36, 0x1b, 0x35, 0x39, 0x2e, 0x3c},
+}
+
+// Configurations of each letter of each number of adjacents.
+var henselClasses [MAX_ADJACENTS + 1]map[byte][]uint8 = buildHenselClasses()
+
+// Returns the mask `mask` rotated 90 degrees clockwise.
+func rotateMask(mask uint8) uint8 {
+	var rotated uint8
+	for i, p := range adjacentOffsets {
+		if mask&(1<<uint(i)) != 0 {
+			rotated |= getMaskBit(-p[1], p[0])
+		}
+	}
+
+	return rotated
+}
+
+// Returns the mask `mask` reflected horizontally.
+func reflectMask(mask uint8) uint8 {
+	var reflected uint8
+	for i, p := range adjacentOffsets {
+		if mask&(1<<uint(i)) != 0 {
+			reflected |= getMaskBit(-p[0], p[1])
+		}
+	}
+
+	return reflected
+}
+
+// Returns the bit of the adjacent in the relative position `dx`, `dy`.
+func getMaskBit(dx, dy int) uint8 {
+	for i, p := range adjacentOffsets {
+		if p[0] == dx && p[1] == dy {
+			return 1 << uint(i)
+		}
+	}
+
+	return 0
+}
+
+// Returns the configurations of the class of the mask `mask`: its rotations and reflections.
+func getMaskClass(mask uint8) []uint8 {
+	class := []uint8{}
+	found := map[uint8]bool{}
+
+	for _, m := range [2]uint8{mask, reflectMask(mask)} {
+		for i := 0; i < 4; i++ {
+			if !found[m] {
+				found[m] = true
+				class = append(class, m)
+			}
+
+			m = rotateMask(m)
+		}
+	}
+
+	return class
+}
+
+// Returns the configurations of each letter of each number of adjacents.
+func buildHenselClasses() [MAX_ADJACENTS + 1]map[byte][]uint8 {
+	classes := [MAX_ADJACENTS + 1]map[byte][]uint8{}
+
+	for n := range classes {
+		classes[n] = map[byte][]uint8{}
+		for i := range henselLetters[n] {
+			var mask uint8
+			if n <= MAX_ADJACENTS/2 {
+				mask = henselMasks[n][i]
+			} else {
+				mask = ^henselMasks[MAX_ADJACENTS-n][i]
+			}
+
+			classes[n][henselLetters[n][i]] = getMaskClass(mask)
+		}
+	}
+
+	return classes
+}
+
+// Returns the mask with the adjacents enabled of a point. `isEnabled` returns whether the
+// adjacent in the relative position `dx`, `dy` is enabled.
+func GetAdjacentsMask(isEnabled func(dx, dy int) bool) uint8 {
+	var mask uint8
+	for i, p := range adjacentOffsets {
+		if isEnabled(p[0], p[1]) {
+			mask |= 1 << uint(i)
+		}
+	}
+
+	return mask
+}
+
+// Parses the letters `letters` of the configurations of `n` adjacents of the rulestring `rule`
+// and stores them in `masks`. `letters` can start with '-' to store the other configurations.
+func parseHenselLetters(rule string, n int, letters string, masks *[256]bool) error {
+	negated := strings.HasPrefix(letters, "-")
+	if negated {
+		letters = letters[1:]
+		if letters == "" {
+			return InvalidRuleError(rule, "the character '-' needs letters")
+		}
+	}
+
+	selected := map[byte]bool{}
+	for i := 0; i < len(letters); i++ {
+		c := letters[i] | 0x20 // lower case.
+		if _, ok := henselClasses[n][c]; !ok {
+			return InvalidRuleError(rule, "invalid character '"+string(letters[i])+"'")
+		}
+
+		if selected[c] {
+			return InvalidRuleError(rule, "the letter '"+string(letters[i])+"' is repeated")
+		}
+
+		selected[c] = true
+	}
+
+	if letters == "" {
+		// All the configurations.
+		for mask := 0; mask < 256; mask++ {
+			if countMask(uint8(mask)) == n {
+				masks[mask] = true
+			}
+		}
+
+		return nil
+	}
+
+	for c, class := range henselClasses[n] {
+		if selected[c] != negated {
+			for _, mask := range class {
+				masks[mask] = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the letters of the configurations of `n` adjacents of `masks` in Hensel notation:
+// empty when all the configurations are in `masks`, or the shortest of the letters or the
+// negated letters.
+func formatHenselLetters(n int, masks *[256]bool) string {
+	letters, negated := "", "-"
+
+	for i := range henselLetters[n] {
+		c := henselLetters[n][i]
+		if masks[henselClasses[n][c][0]] {
+			letters += string(c)
+		} else {
+			negated += string(c)
+		}
+	}
+
+	switch {
+	case negated == "-":
+		return ""
+	case len(negated) < len(letters):
+		return negated
+	default:
+		return letters
+	}
+}
+
+// Returns the number of adjacents enabled of the mask `mask`.
+func countMask(mask uint8) int {
+	return bits.OnesCount8(mask)
+}
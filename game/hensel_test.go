@@ -0,0 +1,257 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the mask of the adjacents in the relative positions `positions`.
+func auxMask(positions ...Position) uint8 {
+	var mask uint8
+	for _, p := range positions {
+		mask |= getMaskBit(p[0], p[1])
+	}
+
+	return mask
+}
+
+// Test the configurations of the letters split all the masks in the 51 isotropic classes.
+func TestHenselClasses(t *testing.T) {
+	assert := assert.New(t)
+	classes := 0
+	found := map[uint8]string{}
+
+	for n := 0; n <= MAX_ADJACENTS; n++ {
+		for c, class := range henselClasses[n] {
+			for _, mask := range class {
+				letter := fmt.Sprintf("%d%c", n, c)
+				assert.Equal(countMask(mask), n, fmt.Sprintf("Invalid adjacents of the class %s.", letter))
+				assert.Equal(found[mask], "", fmt.Sprintf("The mask %d is in the classes %s and %s.", mask, found[mask], letter))
+				found[mask] = letter
+			}
+		}
+
+		classes += len(henselClasses[n])
+	}
+
+	// The configurations of 0 and 8 adjacents have not letter.
+	assert.Equal(classes+2, 51, "Invalid number of classes.")
+	assert.Equal(len(found)+2, 256, "There are masks without class.")
+
+	// Configurations with the shape of each letter. The configurations of 5 to 7 adjacents
+	// are the complements of the configurations of 3 to 1 adjacents with the same letter.
+	n, s, e, w := Position{0, -1}, Position{0, 1}, Position{1, 0}, Position{-1, 0}
+	nw, ne, sw, se := Position{-1, -1}, Position{1, -1}, Position{-1, 1}, Position{1, 1}
+	shapes := map[string]uint8{
+		"1c": auxMask(se),
+		"1e": auxMask(w),
+		"2c": auxMask(ne, se),
+		"2e": auxMask(s, e),
+		"2a": auxMask(n, ne),
+		"2i": auxMask(n, s),
+		"2k": auxMask(n, se),
+		"2n": auxMask(nw, se),
+		"3c": auxMask(nw, ne, se),
+		"3e": auxMask(n, e, s),
+		"3a": auxMask(nw, n, w),
+		"3i": auxMask(nw, w, sw),
+		"3k": auxMask(n, e, sw),
+		"3n": auxMask(nw, ne, e),
+		"3j": auxMask(ne, e, s),
+		"3q": auxMask(nw, s, se),
+		"3r": auxMask(n, ne, s),
+		"3y": auxMask(nw, ne, s),
+		"4c": auxMask(nw, ne, sw, se),
+		"4e": auxMask(n, s, e, w),
+		"4a": auxMask(nw, n, ne, w),
+		"4i": auxMask(nw, n, sw, s),
+		"4k": auxMask(nw, n, e, sw),
+		"4n": auxMask(nw, n, ne, sw),
+		"4j": auxMask(n, w, e, sw),
+		"4q": auxMask(n, ne, e, sw),
+		"4r": auxMask(nw, n, w, e),
+		"4y": auxMask(nw, ne, e, sw),
+		"4t": auxMask(nw, n, ne, s),
+		"4w": auxMask(n, ne, w, sw),
+		"4z": auxMask(ne, w, e, sw),
+	}
+
+	for letter, mask := range shapes {
+		assert.Equal(found[mask], letter, fmt.Sprintf("Invalid class of the configuration %s.", letter))
+
+		if adj := int(letter[0] - '0'); adj < 4 {
+			complement := fmt.Sprintf("%d%c", MAX_ADJACENTS-adj, letter[1])
+			assert.Equal(found[^mask], complement, fmt.Sprintf("Invalid class of the configuration %s.", complement))
+		}
+	}
+
+	assert.Equal(len(shapes), 31, "There are letters without shape.")
+}
+
+// Test the function ParseRule with the Hensel notation.
+func TestParseRuleHensel(t *testing.T) {
+	assert := assert.New(t)
+	rules := map[string]string{
+		"B2-a/S12":                         "B2-a/S12",
+		"B2ceikn/S12":                      "B2-a/S12",
+		"b2-A/s12":                         "B2-a/S12",
+		"B3/S2-i34q":                       "B3/S2-i34q",
+		"B2a/S":                            "B2a/S",
+		"B2ac/S":                           "B2ca/S",
+		"B3ceaiknjqry/S2ceaikn3ceaiknjqry": "B3/S23",
+		"B2-a/S/C3":                        "B2-a/S/C3",
+		"B4ceaiknjqrytw/S":                 "B4-z/S",
+		"B4ceaiknjq/S":                     "B4-rytwz/S",
+		"B4ceaiknj/S":                      "B4ceaiknj/S",
+		"B012345678/S012345678":            "B012345678/S012345678",
+		"B3ceaiknjqry6ceaikn/S23":          "B36/S23",
+		"B5ceaiknjqry/S7ce8":               "B5/S78",
+	}
+
+	for rulestring, canonical := range rules {
+		rule, err := ParseRule(rulestring)
+		assert.Equal(err, nil, fmt.Sprintf("There is an error parsing %s.", rulestring))
+		assert.Equal(rule.String(), canonical, fmt.Sprintf("Invalid canonical rule of %s.", rulestring))
+	}
+
+	assert.Equal(MustParseRule("B3ceaiknjqry/S23").IsTotalistic(), true, "The rule with all the letters is not totalistic.")
+	assert.Equal(MustParseRule("B2-a/S12").IsTotalistic(), false, "The rule B2-a/S12 is totalistic.")
+
+	errors := map[string]string{
+		"B2x/S":    "invalid character 'x'",
+		"B1k/S":    "invalid character 'k'",
+		"B0c/S":    "invalid character 'c'",
+		"B2aa/S":   "the letter 'a' is repeated",
+		"B2-/S":    "the character '-' needs letters",
+		"B2-a-c/S": "invalid character '-'",
+		"2a/3":     "invalid character 'a'",
+	}
+
+	for rulestring, reason := range errors {
+		_, err := ParseRule(rulestring)
+		assert.Equal(err, InvalidRuleError(rulestring, reason), fmt.Sprintf("The error of %s does not match.", rulestring))
+	}
+}
+
+// Test the conditions of the rule depend on the configuration of the adjacents.
+func TestRuleMasks(t *testing.T) {
+	assert := assert.New(t)
+	rule := MustParseRule("B2-a/S12")
+
+	assert.Equal(rule.IsBirthMask(auxMask(Position{0, -1}, Position{1, -1})), false, "The point is born with 2a.")
+	assert.Equal(rule.IsBirthMask(auxMask(Position{0, -1}, Position{0, 1})), true, "The point is not born with 2i.")
+	assert.Equal(rule.IsBirth(2), true, "The point is not born with 2 adjacents.")
+	assert.Equal(rule.IsSurvivalMask(auxMask(Position{1, 1})), true, "The point does not survive with 1c.")
+	assert.Equal(rule.NextStateMask(true, auxMask(Position{1, 1}, Position{0, 1}, Position{-1, 1})), false, "The point survives with 3i.")
+	assert.Equal(rule.NextValueMask(disabled, auxMask(Position{-1, -1}, Position{1, 1})), enabled, "The point is not born with 2n.")
+}
+
+// Test the non-totalistic rule B2i/S: only the points between two points, in a row or in a
+// column, get enabled.
+func TestCycleFuncHensel(t *testing.T) {
+	assert := assert.New(t)
+	rule := MustParseRule("B2i/S")
+
+	for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE, matrix.STORAGE_SPARSE} {
+		options := Options{Rule: rule, Storage: storage}
+		g, _ := NewWithOptions(min, min, []Position{{4, 5}, {6, 5}}, options)
+		g.Cycle()
+
+		positions := map[Position]bool{}
+		g.GetMatrix().ForEachEnabled(func(x, y int) {
+			positions[Position{x, y}] = true
+		})
+
+		// In the rule B2/S the points 5x4 and 5x6 are enabled too.
+		message := fmt.Sprintf("Invalid points with the storage %s.", storage)
+		assert.Equal(positions, map[Position]bool{{5, 5}: true}, message)
+	}
+}
+
+// Test known patterns: the Conway's rule with all the letters is the same as the Conway's
+// rule, the spaceships of tlife (B3/S2-i34q) move in all the storages, and the non-totalistic
+// rules are the same in all the storages, rotations and reflections.
+func TestCycleFuncHenselPatterns(t *testing.T) {
+	assert := assert.New(t)
+
+	rule := MustParseRule("B3ceaiknjqry/S2ceaikn3ceaiknjqry")
+	conway := MustParseRule(CONWAY_RULE)
+	for mask := 0; mask < 256; mask++ {
+		for _, enabled := range []bool{false, true} {
+			message := fmt.Sprintf("Invalid next state of the mask %d.", mask)
+			assert.Equal(rule.NextStateMask(enabled, uint8(mask)), conway.NextState(enabled, countMask(uint8(mask))), message)
+		}
+	}
+
+	// In tlife the glider moves 1 point diagonally each 4 cycles, as in the Conway's rule, and
+	// the T-tetromino is a spaceship that moves 1 point down each 5 cycles.
+	tlife := MustParseRule("B3/S2-i34q")
+	ships := []struct {
+		name      string
+		positions []Position
+		cycles    int
+		dx, dy    int
+	}{
+		{"glider", []Position{{3, 2}, {4, 3}, {2, 4}, {3, 4}, {4, 4}}, 4, 1, 1},
+		{"T-tetromino", []Position{{2, 2}, {3, 2}, {4, 2}, {3, 3}}, 5, 0, 1},
+	}
+
+	for _, ship := range ships {
+		expected := map[Position]bool{}
+		for _, p := range ship.positions {
+			expected[Position{p[0] + ship.dx, p[1] + ship.dy}] = true
+		}
+
+		for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE, matrix.STORAGE_SPARSE} {
+			g, _ := NewWithOptions(min, min, ship.positions, Options{Rule: tlife, Storage: storage})
+			g.Run(uint(ship.cycles))
+
+			message := fmt.Sprintf("Invalid points of the %s with the storage %s.", ship.name, storage)
+			assert.Equal(auxEnabledPositions(g), expected, message)
+		}
+	}
+
+	// Soup of 16x16 in the center of a matrix of 64x64.
+	soup := []Position{}
+	for i := 0; i < 256; i++ {
+		if (i*i*7+i*13)%5 < 2 {
+			soup = append(soup, Position{24 + i%16, 24 + i/16})
+		}
+	}
+
+	for _, rulestring := range []string{"B2-a/S12", "B3/S2-i34q", "B2ek3-anq/S1c2-a3i4ceny"} {
+		rule := MustParseRule(rulestring)
+		games := []*Game{}
+		for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE} {
+			g, _ := NewWithOptions(64, 64, soup, Options{Rule: rule, Storage: storage})
+			games = append(games, g)
+		}
+
+		// The soup rotated 90 degrees.
+		rotated := make([]Position, len(soup))
+		for i, p := range soup {
+			rotated[i] = Position{63 - p[1], p[0]}
+		}
+
+		r, _ := NewWithOptions(64, 64, rotated, Options{Rule: rule, Storage: matrix.STORAGE_DENSE})
+
+		for i := 0; i < 12; i++ {
+			games[0].Cycle()
+			games[1].Cycle()
+			r.Cycle()
+
+			message := fmt.Sprintf("Invalid cycle %d of the rule %s.", i+1, rulestring)
+			assert.Equal(auxEnabledPositions(games[0]), auxEnabledPositions(games[1]), message)
+
+			positions := map[Position]bool{}
+			for p := range auxEnabledPositions(games[1]) {
+				positions[Position{63 - p[1], p[0]}] = true
+			}
+
+			assert.Equal(auxEnabledPositions(r), positions, message+" The rotated soup is different.")
+		}
+	}
+}
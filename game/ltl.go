@@ -0,0 +1,280 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Larger than Life rules: the adjacents are the points at a distance `radius` or less, and
+// the conditions are ranges of adjacents. Example: "R5,C0,M1,S34..58,B34..45,NM" (Bosco's rule).
+// The adjacents are counted using a summed-area table, so the count of each point only needs
+// a sum per row of the neighbourhood, or a single sum in the Moore neighbourhoods.
+
+// Maximum radius of the neighbourhoods of the Larger than Life rules.
+const MAX_LTL_RADIUS int = 500
+
+// Shape of the neighbourhoods of the Larger than Life rules.
+type LtLShape int
+
+const (
+	// The points of the square of side 2*radius+1.
+	LTL_MOORE LtLShape = iota
+
+	// The points at a Manhattan distance `radius` or less.
+	LTL_VON_NEUMANN
+
+	// The points whose center is inside the circle of radius `radius`+0.5.
+	LTL_CIRCULAR
+)
+
+// Letters of the shapes in the rulestrings.
+var ltlShapeLetters map[LtLShape]byte = map[LtLShape]byte{
+	LTL_MOORE:       'M',
+	LTL_VON_NEUMANN: 'N',
+	LTL_CIRCULAR:    'C',
+}
+
+// Neighbourhood and conditions of a Larger than Life rule.
+type ltlRule struct {
+	radius int
+	shape  LtLShape
+
+	// Whether the point is counted as an adjacent of itself.
+	middle bool
+
+	// Minimum and maximum number of adjacents of the birth and the survival.
+	birth    [2]int
+	survival [2]int
+}
+
+// Parses the range `value` of the part `key` of the rulestring `rule`: "min..max" or a number.
+func parseLtLRange(rule string, key byte, value string) ([2]int, error) {
+	limits := strings.SplitN(value, "..", 2)
+	if len(limits) == 1 {
+		limits = append(limits, limits[0])
+	}
+
+	var r [2]int
+	for i, limit := range limits {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return r, InvalidRuleError(rule, fmt.Sprintf("invalid range in the part '%c'", key))
+		}
+
+		r[i] = n
+	}
+
+	if r[0] > r[1] {
+		return r, InvalidRuleError(rule, fmt.Sprintf("the minimum of the part '%c' is greater than the maximum", key))
+	}
+
+	return r, nil
+}
+
+// Parses the Larger than Life rulestring `rule` and returns the rule.
+// The parts are separated by ',' and can be in any order: the radius `R`, the number of states
+// `C` (0 and 1 are 2 states), whether the point is counted `M` (0 or 1), the ranges of
+// survival `S` and birth `B` and the shape of the neighbourhood `N` (M, N or C).
+// The radius and the ranges are required.
+func parseLtLRule(rule string) (*Rule, error) {
+	r := &Rule{states: 2, totalistic: true, ltl: &ltlRule{shape: LTL_MOORE}}
+	found := map[byte]bool{}
+
+	for _, part := range strings.Split(strings.TrimSpace(rule), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, InvalidRuleError(rule, "there is an empty part")
+		}
+
+		key, value := part[0]&^0x20, part[1:] // upper case.
+		if found[key] {
+			return nil, InvalidRuleError(rule, fmt.Sprintf("the part '%c' is repeated", key))
+		}
+
+		found[key] = true
+		var err error
+
+		switch key {
+		case 'R':
+			radius, e := strconv.Atoi(value)
+			if e != nil || radius < 1 || radius > MAX_LTL_RADIUS {
+				err = InvalidRuleError(rule, fmt.Sprintf("the radius must be from 1 to %d", MAX_LTL_RADIUS))
+			}
+
+			r.ltl.radius = radius
+		case 'C':
+			states, e := strconv.Atoi(value)
+			// C0 is the rule without dying states, as C2.
+			if e != nil || states < 0 || states == 1 || states > MAX_STATES {
+				err = InvalidRuleError(rule, fmt.Sprintf("the number of states must be 0 or from 2 to %d", MAX_STATES))
+			}
+
+			if states > 2 {
+				r.states = states
+			}
+		case 'M':
+			if value != "0" && value != "1" {
+				err = InvalidRuleError(rule, "the part 'M' must be 0 or 1")
+			}
+
+			r.ltl.middle = value == "1"
+		case 'S':
+			r.ltl.survival, err = parseLtLRange(rule, key, value)
+		case 'B':
+			r.ltl.birth, err = parseLtLRange(rule, key, value)
+		case 'N':
+			err = InvalidRuleError(rule, "the neighbourhood must be 'M', 'N' or 'C'")
+			for shape, letter := range ltlShapeLetters {
+				if len(value) == 1 && value[0]&^0x20 == letter {
+					r.ltl.shape, err = shape, nil
+				}
+			}
+		default:
+			err = InvalidRuleError(rule, fmt.Sprintf("invalid part '%s'", part))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !found['R'] || !found['S'] || !found['B'] {
+		return nil, InvalidRuleError(rule, "it needs the parts 'R', 'S' and 'B'")
+	}
+
+	return r, nil
+}
+
+// Returns the rulestring of the Larger than Life rule. Example: "R5,C0,M1,S34..58,B34..45,NM".
+func (self *ltlRule) format(states int) string {
+	if states <= 2 {
+		states = 0
+	}
+
+	middle := 0
+	if self.middle {
+		middle = 1
+	}
+
+	return fmt.Sprintf("R%d,C%d,M%d,S%d..%d,B%d..%d,N%c", self.radius, states, middle,
+		self.survival[0], self.survival[1], self.birth[0], self.birth[1], ltlShapeLetters[self.shape])
+}
+
+// Returns the half of the width of the row `dy` of the neighbourhood, relative to the center.
+func (self *ltlRule) getRowRadius(dy int) int {
+	if dy < 0 {
+		dy = -dy
+	}
+
+	switch self.shape {
+	case LTL_VON_NEUMANN:
+		return self.radius - dy
+	case LTL_CIRCULAR:
+		// Points with dx² + dy² < (radius + 0.5)².
+		w := 0
+		for (w+1)*(w+1)+dy*dy <= self.radius*self.radius+self.radius {
+			w++
+		}
+
+		return w
+	default:
+		return self.radius
+	}
+}
+
+// Number of points enabled of the matrix in the rectangles that begin in the top-left corner,
+// including the points beyond the edges up to a distance `radius`.
+type summedAreaTable struct {
+	sums   []int
+	stride int
+	radius int
+}
+
+// Fills the table with the points enabled of the matrix `m`, and the points beyond its edges
+// up to a distance `radius` got using the boundary mode of the matrix.
+func (self *summedAreaTable) build(m *matrix.Matrix, radius int) {
+	width, height := m.GetSize()
+	self.stride, self.radius = width+2*radius+1, radius
+	size := self.stride * (height + 2*radius + 1)
+
+	if cap(self.sums) < size {
+		self.sums = make([]int, size)
+	}
+
+	self.sums = self.sums[:size]
+	for i := 0; i < self.stride; i++ {
+		self.sums[i] = 0
+	}
+
+	for py := 1; py <= height+2*radius; py++ {
+		self.sums[py*self.stride] = 0
+		for px := 1; px < self.stride; px++ {
+			value := 0
+			if x, y, inside := m.Wrap(px-1-radius, py-1-radius); inside {
+				if column, _ := m.GetColumn(x); column[y] == matrix.MATRIX_POINT_ENABLED {
+					value = 1
+				}
+			}
+
+			i := py*self.stride + px
+			self.sums[i] = value + self.sums[i-1] + self.sums[i-self.stride] - self.sums[i-self.stride-1]
+		}
+	}
+}
+
+// Returns the number of points enabled in the rectangle from `x1`, `y1` to `x2`, `y2`, both
+// included. The rectangle can be outside of the matrix up to a distance `radius`.
+func (self *summedAreaTable) sum(x1, y1, x2, y2 int) int {
+	x1, y1 = x1+self.radius, y1+self.radius
+	x2, y2 = x2+self.radius+1, y2+self.radius+1
+	s := self.stride
+	return self.sums[y2*s+x2] - self.sums[y1*s+x2] - self.sums[y2*s+x1] + self.sums[y1*s+x1]
+}
+
+// Returns the number of adjacents enabled of the point `x`, `y`, with the value `value`, in
+// the neighbourhood of the Larger than Life rule `rule`, using the table `self`.
+func (self *summedAreaTable) countAdjacents(rule *ltlRule, x, y, value int) int {
+	var count int
+	if rule.shape == LTL_MOORE {
+		count = self.sum(x-rule.radius, y-rule.radius, x+rule.radius, y+rule.radius)
+	} else {
+		for dy := -rule.radius; dy <= rule.radius; dy++ {
+			w := rule.getRowRadius(dy)
+			count += self.sum(x-w, y+dy, x+w, y+dy)
+		}
+	}
+
+	if !rule.middle && value == matrix.MATRIX_POINT_ENABLED {
+		count--
+	}
+
+	return count
+}
+
+// Returns true when the rule is a Larger than Life rule.
+func (self *Rule) IsLargerThanLife() bool {
+	return self.ltl != nil
+}
+
+// Returns the radius of the neighbourhood of the rule. The rules that are not Larger than Life
+// rules have radius 1.
+func (self *Rule) GetRadius() int {
+	if self.ltl == nil {
+		return 1
+	}
+
+	return self.ltl.radius
+}
+
+// Returns the shape of the neighbourhood of the rule. The rules that are not Larger than Life
+// rules use the Moore neighbourhood.
+func (self *Rule) GetShape() LtLShape {
+	if self.ltl == nil {
+		return LTL_MOORE
+	}
+
+	return self.ltl.shape
+}
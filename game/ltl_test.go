@@ -0,0 +1,180 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function ParseRule with Larger than Life rulestrings.
+func TestParseRuleLtL(t *testing.T) {
+	assert := assert.New(t)
+	rules := map[string]string{
+		"R5,C0,M1,S34..58,B34..45,NM": "R5,C0,M1,S34..58,B34..45,NM",
+		"r5,c0,m1,s34..58,b34..45,nm": "R5,C0,M1,S34..58,B34..45,NM",
+		"R2,B5..5,S4..6":              "R2,C0,M0,S4..6,B5..5,NM",
+		"R3,C4,M0,S2..3,B3,NN":        "R3,C4,M0,S2..3,B3..3,NN",
+		"R7,C2,M1,S10..20,B12..14,NC": "R7,C0,M1,S10..20,B12..14,NC",
+	}
+
+	for rulestring, canonical := range rules {
+		rule, err := ParseRule(rulestring)
+		assert.Equal(err, nil, fmt.Sprintf("There is an error parsing %s.", rulestring))
+		assert.Equal(rule.String(), canonical, fmt.Sprintf("Invalid canonical rule of %s.", rulestring))
+	}
+
+	rule := MustParseRule("R3,C4,M0,S2..3,B3..5,NN")
+	assert.Equal(rule.IsLargerThanLife(), true, "The rule is not Larger than Life.")
+	assert.Equal(rule.GetRadius(), 3, "Invalid radius.")
+	assert.Equal(rule.GetShape(), LTL_VON_NEUMANN, "Invalid shape.")
+	assert.Equal(rule.GetStates(), 4, "Invalid states.")
+	assert.Equal([3]bool{rule.IsBirth(2), rule.IsBirth(5), rule.IsSurvival(3)}, [3]bool{false, true, true}, "Invalid conditions.")
+	assert.Equal(MustParseRule(CONWAY_RULE).GetRadius(), 1, "Invalid radius of the Conway's rule.")
+
+	errors := map[string]string{
+		"R0,S1,B1":      "the radius must be from 1 to 500",
+		"R501,S1,B1":    "the radius must be from 1 to 500",
+		"R2,S1,B1,C300": "the number of states must be 0 or from 2 to 256",
+		"R2,S1,B1,C1":   "the number of states must be 0 or from 2 to 256",
+		"R2,S1,B1,C-1":  "the number of states must be 0 or from 2 to 256",
+		"R2,S1,B1,M2":   "the part 'M' must be 0 or 1",
+		"R2,S1,B1,NX":   "the neighbourhood must be 'M', 'N' or 'C'",
+		"R2,S3..1,B1":   "the minimum of the part 'S' is greater than the maximum",
+		"R2,S1,Bx..2":   "invalid range in the part 'B'",
+		"R2,S1,B1,R3":   "the part 'R' is repeated",
+		"R2,S1,B1,X1":   "invalid part 'X1'",
+		"R2,S1,,B1":     "there is an empty part",
+		"R2,S1":         "it needs the parts 'R', 'S' and 'B'",
+	}
+
+	for rulestring, reason := range errors {
+		_, err := ParseRule(rulestring)
+		assert.Equal(err, InvalidRuleError(rulestring, reason), fmt.Sprintf("The error of %s does not match.", rulestring))
+	}
+}
+
+// Test the adjacents counted with the summed-area table are the same as counting them one by
+// one, in all the shapes and boundaries.
+func TestSummedAreaTable(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+
+	for _, boundary := range []matrix.Boundary{matrix.BOUNDARY_DEAD, matrix.BOUNDARY_TORUS, matrix.BOUNDARY_KLEIN} {
+		m, _ := matrix.NewWithBoundary(12, 10, boundary)
+		for i := 0; i < 50; i++ {
+			m.EnablePoint(r.Intn(12), r.Intn(10))
+		}
+
+		for _, shape := range []LtLShape{LTL_MOORE, LTL_VON_NEUMANN, LTL_CIRCULAR} {
+			for _, radius := range []int{1, 3, 7} {
+				rule := &ltlRule{radius: radius, shape: shape}
+				table := summedAreaTable{}
+				table.build(m, radius)
+
+				for x := 0; x < 12; x++ {
+					for y := 0; y < 10; y++ {
+						expected := 0
+						for dx := -radius; dx <= radius; dx++ {
+							for dy := -radius; dy <= radius; dy++ {
+								inside := dx*dx+dy*dy <= radius*radius+radius
+								if shape == LTL_VON_NEUMANN {
+									inside = abs(dx)+abs(dy) <= radius
+								} else if shape == LTL_MOORE {
+									inside = true
+								}
+
+								ax, ay, ok := m.Wrap(x+dx, y+dy)
+								if enabled, _ := m.IsEnabled(ax, ay); inside && ok && enabled && (dx != 0 || dy != 0) {
+									expected++
+								}
+							}
+						}
+
+						value, _ := m.GetPoint(x, y)
+						message := fmt.Sprintf("Invalid adjacents of %dx%d (%s, shape %d, radius %d).", x, y, boundary, shape, radius)
+						assert.Equal(table.countAdjacents(rule, x, y, value), expected, message)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Returns the absolute value of `n`.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// Test the Larger than Life rules of radius 1 equivalent to the Conway's rule, with and
+// without the point in the count, run the same cycles as the Conway's rule.
+func TestCycleFuncLtLConway(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(2))
+	soup := make([]Position, 300)
+	for i := range soup {
+		soup[i] = Position{10 + r.Intn(20), 10 + r.Intn(20)}
+	}
+
+	conway, _ := NewWithOptions(40, 40, soup, Options{Boundary: matrix.BOUNDARY_TORUS})
+	games := []*Game{}
+	for _, rule := range []string{"R1,C0,M0,S2..3,B3..3,NM", "R1,C0,M1,S3..4,B3..3,NM"} {
+		options := Options{Rule: MustParseRule(rule), Storage: matrix.STORAGE_DENSE, Boundary: matrix.BOUNDARY_TORUS, Workers: 3}
+		g, err := NewWithOptions(40, 40, soup, options)
+		assert.Equal(err, nil, "There is an error.")
+		games = append(games, g)
+	}
+
+	for i := 0; i < 30; i++ {
+		conway.Cycle()
+		for _, g := range games {
+			g.Cycle()
+			message := fmt.Sprintf("Invalid cycle %d of the rule %s.", i+1, g.GetRule())
+			assert.Equal(auxEnabledPositions(g), auxEnabledPositions(conway), message)
+		}
+	}
+
+	_, err := NewWithOptions(40, 40, soup, Options{Rule: MustParseRule("R2,S1,B1"), Storage: matrix.STORAGE_SPARSE})
+	reason := "the Larger than Life rules need the dense storage"
+	assert.Equal(err, InvalidRuleError("R2,C0,M0,S1..1,B1..1,NM", reason), "The error does not match.")
+}
+
+// Test the Bosco's rule: a point survives with 34 to 58 adjacents, itself included, and it is
+// born with 34 to 45 adjacents, in the square of radius 5.
+func TestCycleFuncBosco(t *testing.T) {
+	assert := assert.New(t)
+	options := Options{Rule: MustParseRule("R5,C0,M1,S34..58,B34..45,NM"), Storage: matrix.STORAGE_DENSE}
+
+	// A square of 7x7 (49 points) in the center of the matrix.
+	square := []Position{}
+	for x := 20; x < 27; x++ {
+		for y := 20; y < 27; y++ {
+			square = append(square, Position{x, y})
+		}
+	}
+
+	g, _ := NewWithOptions(48, 48, square, options)
+	g.Cycle()
+
+	// The center of the square has 49 adjacents (itself included) and the corner has 36, so
+	// they survive. The point at 3 points of the edge has 21 adjacents and it is not born, and
+	// the point at 1 point has 35 adjacents and it is born.
+	center, _ := g.GetMatrix().IsEnabled(23, 23)
+	corner, _ := g.GetMatrix().IsEnabled(20, 20)
+	outside, _ := g.GetMatrix().IsEnabled(17, 23)
+	near, _ := g.GetMatrix().IsEnabled(19, 23)
+	assert.Equal([4]bool{center, corner, outside, near}, [4]bool{true, true, false, true}, "Invalid points of the square.")
+
+	// The dense storage is used by default.
+	g, err := NewWithRule(48, 48, square, options.Rule)
+	assert.Equal(err, nil, "There is an error with the default storage.")
+	g.Cycle()
+	near, _ = g.GetMatrix().IsEnabled(19, 23)
+	assert.Equal(near, true, "Invalid points with the default storage.")
+}
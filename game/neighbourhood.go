@@ -0,0 +1,164 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Point counted as adjacent of the points of the game: its position relative to the point,
+// and the number of times it is counted.
+type Adjacent struct {
+	Dx, Dy int
+	Weight int
+}
+
+// Points counted as adjacents of the points of the game. The rules use the sum of the weights
+// of the adjacents enabled as the number of adjacents.
+type Neighbourhood struct {
+	adjacents []Adjacent
+}
+
+// Maximum distance of the adjacents to the point.
+const MAX_NEIGHBOURHOOD_RADIUS int = 10
+
+// Maximum weight of an adjacent.
+const MAX_WEIGHT int = 9
+
+// Names of the common neighbourhoods and their masks. See `ParseNeighbourhood`.
+var neighbourhoodNames map[string]string = map[string]string{
+	"moore":       "111/101/111",
+	"vonneumann":  "010/101/010",
+	"von-neumann": "010/101/010",
+	// The hexagonal grid emulated in the square grid: the north-east and the south-west
+	// adjacents are ignored, so the rows are shifted half point.
+	"hexagonal": "110/101/011",
+	"hex":       "110/101/011",
+}
+
+// Makes a new neighbourhood with the adjacents `adjacents`.
+// Returns an error whether there are no adjacents, an adjacent is repeated or it is too far,
+// or a weight is not positive.
+func NewNeighbourhood(adjacents []Adjacent) (*Neighbourhood, error) {
+	if len(adjacents) == 0 {
+		return nil, InvalidNeighbourhoodError("it has not adjacents")
+	}
+
+	found := map[Position]bool{}
+	for _, a := range adjacents {
+		p := Position{a.Dx, a.Dy}
+		switch {
+		case found[p]:
+			return nil, InvalidNeighbourhoodError(fmt.Sprintf("the adjacent (%d, %d) is repeated", a.Dx, a.Dy))
+		case a.Dx < -MAX_NEIGHBOURHOOD_RADIUS || a.Dx > MAX_NEIGHBOURHOOD_RADIUS ||
+			a.Dy < -MAX_NEIGHBOURHOOD_RADIUS || a.Dy > MAX_NEIGHBOURHOOD_RADIUS:
+			return nil, InvalidNeighbourhoodError(fmt.Sprintf("the adjacent (%d, %d) is too far", a.Dx, a.Dy))
+		case a.Weight < 1 || a.Weight > MAX_WEIGHT:
+			return nil, InvalidNeighbourhoodError(fmt.Sprintf("the weight of the adjacent (%d, %d) is invalid", a.Dx, a.Dy))
+		}
+
+		found[p] = true
+	}
+
+	n := &Neighbourhood{make([]Adjacent, len(adjacents))}
+	copy(n.adjacents, adjacents)
+	return n, nil
+}
+
+// Parses the neighbourhood `text` and returns it. The text is the name of a common
+// neighbourhood, "moore", "vonneumann" or "hexagonal", or a mask: rows of digits separated by
+// '/', with the weight of each point and the point in the center. Example: "010/101/010".
+// Returns an error whether the text is invalid.
+func ParseNeighbourhood(text string) (*Neighbourhood, error) {
+	text = strings.TrimSpace(text)
+	if mask, ok := neighbourhoodNames[strings.ToLower(text)]; ok {
+		text = mask
+	}
+
+	rows := strings.Split(text, "/")
+	size := len(rows)
+	if size%2 == 0 || size > 2*MAX_NEIGHBOURHOOD_RADIUS+1 {
+		return nil, InvalidNeighbourhoodError("the mask must have an odd number of rows, up to 21")
+	}
+
+	adjacents := []Adjacent{}
+	for y, row := range rows {
+		if len(row) != size {
+			return nil, InvalidNeighbourhoodError("the mask must be square")
+		}
+
+		for x := 0; x < len(row); x++ {
+			if row[x] < '0' || row[x] > '9' {
+				return nil, InvalidNeighbourhoodError(fmt.Sprintf("invalid character '%c'", row[x]))
+			}
+
+			if weight := int(row[x] - '0'); weight > 0 {
+				adjacents = append(adjacents, Adjacent{x - size/2, y - size/2, weight})
+			}
+		}
+	}
+
+	return NewNeighbourhood(adjacents)
+}
+
+// Returns the Moore neighbourhood: the 8 points around the point.
+func NewMooreNeighbourhood() *Neighbourhood {
+	n, _ := ParseNeighbourhood("moore")
+	return n
+}
+
+// Returns the adjacents of the neighbourhood.
+func (self *Neighbourhood) GetAdjacents() []Adjacent {
+	adjacents := make([]Adjacent, len(self.adjacents))
+	copy(adjacents, self.adjacents)
+	return adjacents
+}
+
+// Returns the maximum number of adjacents: the sum of the weights.
+func (self *Neighbourhood) GetMaxCount() int {
+	count := 0
+	for _, a := range self.adjacents {
+		count += a.Weight
+	}
+
+	return count
+}
+
+// Returns the maximum distance, horizontal or vertical, of the adjacents to the point.
+func (self *Neighbourhood) GetRadius() int {
+	radius := 0
+	for _, a := range self.adjacents {
+		for _, d := range [4]int{a.Dx, -a.Dx, a.Dy, -a.Dy} {
+			if d > radius {
+				radius = d
+			}
+		}
+	}
+
+	return radius
+}
+
+// Returns true when the neighbourhood is the Moore neighbourhood.
+func (self *Neighbourhood) IsMoore() bool {
+	return self.String() == neighbourhoodNames["moore"]
+}
+
+// Returns the mask of the neighbourhood. Example: "010/101/010".
+func (self Neighbourhood) String() string {
+	radius := self.GetRadius()
+	size := 2*radius + 1
+	rows := make([][]byte, size)
+	for y := range rows {
+		rows[y] = []byte(strings.Repeat("0", size))
+	}
+
+	for _, a := range self.adjacents {
+		rows[a.Dy+radius][a.Dx+radius] = byte('0' + a.Weight)
+	}
+
+	parts := make([]string, size)
+	for y, row := range rows {
+		parts[y] = string(row)
+	}
+
+	return strings.Join(parts, "/")
+}
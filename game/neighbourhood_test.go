@@ -0,0 +1,176 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function ParseNeighbourhood.
+func TestParseNeighbourhood(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		text     string
+		mask     string
+		maxCount int
+		radius   int
+	}{
+		{"moore", "111/101/111", 8, 1},
+		{"Moore", "111/101/111", 8, 1},
+		{"vonneumann", "010/101/010", 4, 1},
+		{"von-neumann", "010/101/010", 4, 1},
+		{"hexagonal", "110/101/011", 6, 1},
+		{"hex", "110/101/011", 6, 1},
+		{"222/202/222", "222/202/222", 16, 1},
+		{"00100/00000/10001/00000/00100", "00100/00000/10001/00000/00100", 4, 2},
+		{"00000/01110/01010/01110/00000", "111/101/111", 8, 1},
+		{" 111/111/111 ", "111/111/111", 9, 1},
+	}
+
+	for _, test := range tests {
+		n, err := ParseNeighbourhood(test.text)
+		assert.Equal(err, nil, fmt.Sprintf("There is an error parsing %s.", test.text))
+		assert.Equal(n.String(), test.mask, fmt.Sprintf("Invalid mask of %s.", test.text))
+		assert.Equal(n.GetMaxCount(), test.maxCount, fmt.Sprintf("Invalid maximum count of %s.", test.text))
+		assert.Equal(n.GetRadius(), test.radius, fmt.Sprintf("Invalid radius of %s.", test.text))
+		assert.Equal(n.IsMoore(), test.mask == "111/101/111", fmt.Sprintf("Invalid Moore check of %s.", test.text))
+	}
+
+	hex, _ := ParseNeighbourhood("hex")
+	adjacents := []Adjacent{{-1, -1, 1}, {0, -1, 1}, {-1, 0, 1}, {1, 0, 1}, {0, 1, 1}, {1, 1, 1}}
+	assert.Equal(hex.GetAdjacents(), adjacents, "Invalid adjacents of the hexagonal neighbourhood.")
+	assert.Equal(NewMooreNeighbourhood().IsMoore(), true, "The Moore neighbourhood is not Moore.")
+}
+
+// Test the errors of the neighbourhoods.
+func TestNeighbourhoodError(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[string]string{
+		"1/1":                          "the mask must have an odd number of rows, up to 21",
+		"11/11":                        "the mask must have an odd number of rows, up to 21",
+		strings.Repeat("1/", 22) + "1": "the mask must have an odd number of rows, up to 21",
+		"010/10/010":                   "the mask must be square",
+		"0a0/101/010":                  "invalid character 'a'",
+		"000/000/000":                  "it has not adjacents",
+	}
+
+	for text, reason := range tests {
+		n, err := ParseNeighbourhood(text)
+		assert.Equal(n, (*Neighbourhood)(nil), fmt.Sprintf("The neighbourhood %q is not nil.", text))
+		assert.Equal(err, InvalidNeighbourhoodError(reason), fmt.Sprintf("The error of %q does not match.", text))
+	}
+
+	adjacents := map[string][]Adjacent{
+		"the adjacent (1, 0) is repeated":              {{1, 0, 1}, {1, 0, 2}},
+		"the adjacent (11, 0) is too far":              {{11, 0, 1}},
+		"the adjacent (0, -11) is too far":             {{0, -11, 1}},
+		"the weight of the adjacent (1, 1) is invalid": {{1, 1, 0}},
+		"the weight of the adjacent (1, 0) is invalid": {{1, 0, 10}},
+	}
+
+	for reason, a := range adjacents {
+		_, err := NewNeighbourhood(a)
+		assert.Equal(err, InvalidNeighbourhoodError(reason), fmt.Sprintf("The error %q does not match.", reason))
+	}
+}
+
+// Test the rule B1/S with the von Neumann neighbourhood: a point makes a cross.
+func TestCycleFuncVonNeumann(t *testing.T) {
+	assert := assert.New(t)
+	n, _ := ParseNeighbourhood("vonneumann")
+	expected := map[Position]bool{{5, 4}: true, {4, 5}: true, {6, 5}: true, {5, 6}: true}
+
+	for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE, matrix.STORAGE_SPARSE} {
+		options := Options{Rule: MustParseRule("B1/S"), Storage: storage, Neighbourhood: n}
+		g, _ := NewWithOptions(min, min, []Position{{5, 5}}, options)
+		g.Cycle()
+
+		message := fmt.Sprintf("Invalid points with the storage %s.", storage)
+		assert.Equal(auxEnabledPositions(g), expected, message)
+		assert.Equal(g.GetNeighbourhood(), n, "Invalid neighbourhood of the game.")
+	}
+}
+
+// Test the neighbourhoods with weights: the Conway's rule with all the weights doubled is the
+// same as the Conway's rule.
+func TestCycleFuncWeights(t *testing.T) {
+	assert := assert.New(t)
+	n, _ := ParseNeighbourhood("222/202/222")
+	rule := MustParseRule("B6/S4,6")
+	glider := []Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+
+	for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE, matrix.STORAGE_SPARSE} {
+		weighted, _ := NewWithOptions(20, 20, glider, Options{Rule: rule, Storage: storage, Neighbourhood: n})
+		conway, _ := NewWithOptions(20, 20, glider, Options{Storage: storage})
+
+		for i := 0; i < 12; i++ {
+			weighted.Cycle()
+			conway.Cycle()
+
+			message := fmt.Sprintf("Invalid cycle %d with the storage %s.", i+1, storage)
+			assert.Equal(auxEnabledPositions(weighted), auxEnabledPositions(conway), message)
+		}
+	}
+}
+
+// Test the neighbourhoods are the same in all the storages, with rules of more than 8
+// adjacents.
+func TestCycleFuncNeighbourhoods(t *testing.T) {
+	assert := assert.New(t)
+
+	// Soup of 16x16 in the center of a matrix of 64x64.
+	soup := []Position{}
+	for i := 0; i < 256; i++ {
+		if (i*i*7+i*13)%5 < 2 {
+			soup = append(soup, Position{24 + i%16, 24 + i/16})
+		}
+	}
+
+	tests := []struct{ neighbourhood, rule string }{
+		{"hexagonal", "B2/S34"},
+		{"11111/11111/11011/11111/11111", "B8,9,10/S7,8,9,10,11"},
+		{"010/121/010", "B2,3/S2,4"},
+	}
+
+	for _, test := range tests {
+		n, _ := ParseNeighbourhood(test.neighbourhood)
+		games := []*Game{}
+		for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE, matrix.STORAGE_SPARSE} {
+			options := Options{Rule: MustParseRule(test.rule), Storage: storage, Neighbourhood: n}
+			g, _ := NewWithOptions(64, 64, soup, options)
+			games = append(games, g)
+		}
+
+		for i := 0; i < 8; i++ {
+			for _, g := range games {
+				g.Cycle()
+			}
+
+			message := fmt.Sprintf("Invalid cycle %d of the neighbourhood %s.", i+1, test.neighbourhood)
+			assert.Equal(auxEnabledPositions(games[0]), auxEnabledPositions(games[1]), message)
+			assert.Equal(auxEnabledPositions(games[0]), auxEnabledPositions(games[2]), message)
+		}
+	}
+}
+
+// Test the non-totalistic and the Larger than Life rules only use their neighbourhoods.
+func TestNewGameNeighbourhoodError(t *testing.T) {
+	assert := assert.New(t)
+	n, _ := ParseNeighbourhood("vonneumann")
+
+	for _, rulestring := range []string{"B2-a/S12", "R2,C0,M0,S2..3,B3..3,NM"} {
+		rule := MustParseRule(rulestring)
+		_, err := NewWithOptions(min, min, nil, Options{Rule: rule, Storage: matrix.STORAGE_DENSE, Neighbourhood: n})
+		reason := "the rule can not use other neighbourhoods"
+		assert.Equal(err, InvalidRuleError(rule.String(), reason), fmt.Sprintf("The error of %s does not match.", rulestring))
+	}
+
+	// The Moore neighbourhood is allowed.
+	options := Options{Rule: MustParseRule("B2-a/S12"), Neighbourhood: NewMooreNeighbourhood()}
+	g, err := NewWithOptions(min, min, nil, options)
+	assert.Equal(err, nil, "There is an error with the Moore neighbourhood.")
+	assert.Equal(g.GetNeighbourhood().IsMoore(), true, "The neighbourhood of the game is not Moore.")
+}
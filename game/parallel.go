@@ -0,0 +1,81 @@
+package game
+
+import (
+	"sync"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Splits the range [0, `size`) in `workers` strips and runs `task` with each one of them in a
+// goroutine. It waits until all the tasks finish.
+// With one worker, or less, the task runs in the current goroutine.
+func runStrips(size, workers int, task func(from, to int)) {
+	if workers > size {
+		workers = size
+	}
+
+	if workers <= 1 {
+		task(0, size)
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func(from, to int) {
+			defer wg.Done()
+			task(from, to)
+		}(size*i/workers, size*(i+1)/workers)
+	}
+
+	wg.Wait()
+}
+
+// Generates the columns from `from` to `to` (not included) of the next cycle of the matrix
+// `m` in the matrix `next`.
+func (self *Game) stepDenseColumns(m, next *matrix.Matrix, from, to int) {
+	for i := from; i < to; i++ {
+		current, _ := m.GetColumn(i)
+		column, _ := next.GetColumn(i)
+
+		for j := range column {
+			switch {
+			case self.rule.ltl != nil:
+				adj := self.table.countAdjacents(self.rule.ltl, i, j, current[j])
+				column[j] = self.rule.NextValue(current[j], adj)
+			case self.rule.IsTotalistic():
+				column[j] = self.rule.NextValue(current[j], self.countAdjacents(i, j))
+			default:
+				column[j] = self.rule.NextValueMask(current[j], self.getAdjacentsMask(i, j))
+			}
+		}
+	}
+}
+
+// Generates the next cycle of the matrix `m` in the buffer and swaps them.
+// The columns are split in strips, one per worker.
+func (self *Game) cycleDense(m *matrix.Matrix) error {
+	var err error
+	width, height := m.GetSize()
+
+	next, ok := self.buffer.(*matrix.Matrix)
+	if !ok || next.GetWidth() != width || next.GetHeight() != height {
+		if next, err = matrix.NewWithTopology(width, height, m.GetBoundary(), m.GetTopology()); err != nil {
+			return err
+		}
+
+		self.buffer = next
+	}
+
+	if self.rule.ltl != nil {
+		self.table.build(m, self.rule.ltl.radius)
+	}
+
+	runStrips(width, self.workers, func(from, to int) {
+		self.stepDenseColumns(m, next, from, to)
+	})
+
+	next.Recount()
+	return m.Swap(next)
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function runStrips covers all the range without overlaps.
+func TestRunStrips(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, workers := range []int{0, 1, 2, 3, 7, 50} {
+		covered := make([]int, 20)
+		runStrips(len(covered), workers, func(from, to int) {
+			for i := from; i < to; i++ {
+				covered[i]++
+			}
+		})
+
+		for i, c := range covered {
+			assert.Equal(c, 1, fmt.Sprintf("The position %d is covered %d times (%d workers).", i, c, workers))
+		}
+	}
+}
+
+// Test the parallel cycles generate the same points than the serial cycles.
+func TestCycleFuncParallel(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+	w, h := 97, 45
+	positions := auxRandomPositions(r, w, h, w*h/3)
+
+	for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE} {
+		for _, boundary := range []matrix.Boundary{matrix.BOUNDARY_DEAD, matrix.BOUNDARY_KLEIN} {
+			options := Options{Rule: MustParseRule("B36/S23"), Boundary: boundary, Storage: storage}
+			serial, _ := NewWithOptions(w, h, positions, options)
+
+			parallels := []*Game{}
+			for _, workers := range []int{2, 3, 8, 100} {
+				options.Workers = workers
+				g, _ := NewWithOptions(w, h, positions, options)
+				assert.Equal(g.GetWorkers(), workers, "Invalid number of workers.")
+				parallels = append(parallels, g)
+			}
+
+			for i := 0; i < 20; i++ {
+				serial.Cycle()
+				expected := auxEnabledPositions(serial)
+
+				for _, g := range parallels {
+					assert.Equal(g.Cycle(), nil, "There is an error.")
+
+					message := fmt.Sprintf("Invalid cycle %d (%s, %s, %d workers).", i+1, storage, boundary, g.GetWorkers())
+					assert.Equal(auxEnabledPositions(g), expected, message)
+					assert.Equal(g.matrix.GetPointsEnabled(), serial.matrix.GetPointsEnabled(), message)
+					assert.Equal(g.GetCyclesNum(), serial.GetCyclesNum(), message)
+				}
+			}
+		}
+	}
+}
+
+// Test the parallel cycles of the packed matrix generate the same words than the serial ones.
+func TestCycleFuncParallelPackedWords(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(2))
+	w, h := 200, 64
+	positions := auxRandomPositions(r, w, h, w*h/3)
+	serial, _ := New(w, h, positions)
+	parallel, _ := NewWithOptions(w, h, positions, Options{Workers: 4})
+
+	for i := 0; i < 10; i++ {
+		serial.Cycle()
+		parallel.SetWorkers(1 + i%5)
+		parallel.Cycle()
+
+		for y := 0; y < h; y++ {
+			expected, _ := serial.matrix.(*matrix.Packed).GetRow(y)
+			row, _ := parallel.matrix.(*matrix.Packed).GetRow(y)
+			assert.Equal(row, expected, fmt.Sprintf("Invalid row %d in the cycle %d.", y, i+1))
+		}
+	}
+}
+
+func benchmarkCycleWorkers(b *testing.B, storage matrix.Storage, workers int) {
+	r := rand.New(rand.NewSource(1))
+	options := Options{Storage: storage, Workers: workers}
+	g, _ := NewWithOptions(1024, 1024, auxRandomPositions(r, 1024, 1024, 1024*1024/3), options)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		g.Cycle()
+	}
+}
+
+func BenchmarkCyclePacked1Worker(b *testing.B)  { benchmarkCycleWorkers(b, matrix.STORAGE_PACKED, 1) }
+func BenchmarkCyclePacked4Workers(b *testing.B) { benchmarkCycleWorkers(b, matrix.STORAGE_PACKED, 4) }
+func BenchmarkCycleDense4Workers(b *testing.B)  { benchmarkCycleWorkers(b, matrix.STORAGE_DENSE, 4) }
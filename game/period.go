@@ -0,0 +1,334 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Detection of the periodic states: the hash of the state of each cycle is saved and, when
+// a hash is repeated, the game is in a loop. Still lifes have period 1.
+// In the unbounded universes the states are moved to the corner of their bounding box
+// before hashing them, so the spaceships, that repeat the state in other position, are
+// detected too. In the bounded ones the edges change the moved states, so the states are
+// only repeated in the same position.
+// The hashes have 64 bits, so two different states with the same hash are very unlikely,
+// but only the points of the first state are saved: when the hashes match, the game is
+// replayed from it to compare the states.
+
+// Returns the hash of the position `x`, `y`. Mix function of SplitMix64.
+func hashPosition(x, y int) uint64 {
+	h := uint64(uint32(x))<<32 | uint64(uint32(y))
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// Returns the hash of the points enabled of the universe `m`, moved `dx`, `dy` points.
+// The hash does not depend on the order of the points, so it is the same in all the storages.
+// The dying points of the dense matrices are hashed too, with their value.
+func hashUniverse(m matrix.Universe, dx, dy int) uint64 {
+	var h uint64
+	if dense, ok := m.(*matrix.Matrix); ok {
+		dense.ForEachPoint(func(x, y, value int) {
+			h += hashPosition(x+dx, y+dy) * uint64(value)
+		})
+	} else {
+		m.ForEachEnabled(func(x, y int) {
+			h += hashPosition(x+dx, y+dy)
+		})
+	}
+
+	return h ^ uint64(m.GetPointsEnabled())
+}
+
+// Returns the points not disabled of the universe `m`, moved `dx`, `dy` points, with their
+// values: x, y and value of each point. They are sorted, so they are the same in all
+// the storages.
+func getUniversePoints(m matrix.Universe, dx, dy int) [][3]int {
+	points := [][3]int{}
+	if dense, ok := m.(*matrix.Matrix); ok {
+		dense.ForEachPoint(func(x, y, value int) {
+			points = append(points, [3]int{x + dx, y + dy, value})
+		})
+	} else {
+		m.ForEachEnabled(func(x, y int) {
+			points = append(points, [3]int{x + dx, y + dy, matrix.MATRIX_POINT_ENABLED})
+		})
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		a, b := points[i], points[j]
+		return a[0] < b[0] || a[0] == b[0] && a[1] < b[1]
+	})
+
+	return points
+}
+
+// Checks if the points `a` and `b`, got with `getUniversePoints`, are the same.
+func isSameState(a, b [][3]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Cycle and position of the bounding box of a game state.
+type stateRecord struct {
+	cycle uint
+	x, y  int
+}
+
+// Hashes of the game states, used to detect the period.
+type periodDetector struct {
+	// Cycle and position of the states of each hash. A hash can have several states when
+	// they collide.
+	history map[uint64][]stateRecord
+
+	// Points of the first state saved, and its cycle. The game is replayed from them to get
+	// the states of the history.
+	initial      [][3]int
+	initialCycle uint
+
+	// Period, the cycle where the loop starts and the displacement of the points in each
+	// period. Only valid when `found` is true.
+	period uint
+	start  uint
+	dx, dy int
+	found  bool
+
+	// Whether `Run` stops when the period is found.
+	stopOnPeriod bool
+}
+
+// Returns the position where the current state is moved from before hashing it: the corner
+// of the bounding box in the unbounded universes and 0, 0 in the bounded ones. The hexagonal
+// and the triangular topologies only use bounded universes, so their states are not moved.
+func (self *Game) getStateOrigin() (int, int) {
+	if self.matrix.IsBounded() {
+		return 0, 0
+	}
+
+	box, _ := self.matrix.GetBoundingBox()
+	return box.MinX, box.MinY
+}
+
+// Saves the hash of the current game state and checks if it is repeated.
+func (self *Game) recordState() {
+	d := self.detector
+	if d == nil || d.found {
+		return
+	}
+
+	if d.initial == nil {
+		d.initial, d.initialCycle = getUniversePoints(self.matrix, 0, 0), self.cycles
+	}
+
+	x, y := self.getStateOrigin()
+	h := hashUniverse(self.matrix, -x, -y)
+
+	for _, r := range d.history[h] {
+		if !self.isSavedState(r, x, y) {
+			// Different states with the same hash.
+			continue
+		}
+
+		d.period, d.start, d.found = self.cycles-r.cycle, r.cycle, true
+		d.dx, d.dy = x-r.x, y-r.y
+
+		// The history is not needed anymore.
+		d.history, d.initial = nil, nil
+		return
+	}
+
+	d.history[h] = append(d.history[h], stateRecord{self.cycles, x, y})
+}
+
+// Checks if the current state, moved from the position `x`, `y`, is the state of the record
+// `r`. The state of the record is got replaying the game from the first state saved.
+func (self *Game) isSavedState(r stateRecord, x, y int) bool {
+	d := self.detector
+	replay, err := self.newReplay(d.initial)
+	if err != nil {
+		return false
+	}
+
+	for i := d.initialCycle; i < r.cycle; i++ {
+		if replay.Cycle() != nil {
+			return false
+		}
+	}
+
+	return isSameState(getUniversePoints(replay.matrix, -r.x, -r.y), getUniversePoints(self.matrix, -x, -y))
+}
+
+// Returns a new game with the settings of the game and the points `points`, got with
+// `getUniversePoints`. It does not detect the period.
+func (self *Game) newReplay(points [][3]int) (*Game, error) {
+	var storage matrix.Storage
+	switch self.matrix.(type) {
+	case *matrix.Packed:
+		storage = matrix.STORAGE_PACKED
+	case *matrix.Matrix:
+		storage = matrix.STORAGE_DENSE
+	case *matrix.Sparse:
+		storage = matrix.STORAGE_SPARSE
+	default:
+		return nil, UnsupportedUniverseError(self.matrix)
+	}
+
+	width, height := self.matrix.GetSize()
+	m, err := matrix.NewUniverseWithTopology(storage, width, height, self.matrix.GetBoundary(), self.topology)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range points {
+		if dense, ok := m.(*matrix.Matrix); ok {
+			err = dense.SetPoint(p[0], p[1], p[2])
+		} else {
+			err = m.EnablePoint(p[0], p[1])
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	g := &Game{matrix: m, rule: self.rule, workers: self.workers, neighbourhood: self.neighbourhood, topology: self.topology}
+	return g, nil
+}
+
+// Returns the period of the game, the cycle where the loop starts and whether the period
+// is found. The still lifes have period 1. In the unbounded universes, the period of the
+// spaceships is the number of cycles to repeat the state in other position.
+// See `GetDisplacement`.
+// The period is only detected when the game is made with the option `DetectPeriod`.
+func (self *Game) GetPeriod() (uint, uint, bool) {
+	if self.detector == nil || !self.detector.found {
+		return 0, 0, false
+	}
+
+	return self.detector.period, self.detector.start, true
+}
+
+// Returns the displacement of the points in each period and whether the period is found.
+// It is 0, 0 in the oscillators and the still lifes.
+func (self *Game) GetDisplacement() (int, int, bool) {
+	if self.detector == nil || !self.detector.found {
+		return 0, 0, false
+	}
+
+	return self.detector.dx, self.detector.dy, true
+}
+
+// Checks if the game is a spaceship: the period is found and the points are moved.
+func (self *Game) IsSpaceship() bool {
+	dx, dy, found := self.GetDisplacement()
+	return found && (dx != 0 || dy != 0)
+}
+
+// Returns the speed of the spaceship in c/N notation, with its direction. Examples:
+// "c/4 diagonal" for the glider, "c/2 orthogonal" for the LWSS. It is empty when the game
+// is not a spaceship.
+func (self *Game) GetSpeed() string {
+	if !self.IsSpaceship() {
+		return ""
+	}
+
+	return FormatSpeed(self.detector.dx, self.detector.dy, self.detector.period)
+}
+
+// Returns the greatest common divisor of `a` and `b`.
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
+
+// Returns the speed, in c/N notation, of a displacement `dx`, `dy` in `period` cycles,
+// with its direction: orthogonal, diagonal or oblique.
+// Examples: "c/4 diagonal", "2c/5 orthogonal", "(2,1)c/6 oblique". It is empty without
+// displacement.
+func FormatSpeed(dx, dy int, period uint) string {
+	if dx < 0 {
+		dx = -dx
+	}
+
+	if dy < 0 {
+		dy = -dy
+	}
+
+	if dx < dy {
+		dx, dy = dy, dx
+	}
+
+	if dx == 0 || period == 0 {
+		return ""
+	}
+
+	if dy != 0 && dx != dy {
+		return fmt.Sprintf("(%d,%d)c/%d oblique", dx, dy, period)
+	}
+
+	direction := "orthogonal"
+	if dx == dy {
+		direction = "diagonal"
+	}
+
+	d := gcd(dx, int(period))
+	n, p := dx/d, int(period)/d
+	speed := "c"
+
+	if n != 1 {
+		speed = fmt.Sprintf("%dc", n)
+	}
+
+	if p != 1 {
+		speed = fmt.Sprintf("%s/%d", speed, p)
+	}
+
+	return speed + " " + direction
+}
+
+// Removes the states saved to detect the period. It must be called when the points are
+// changed outside of the game cycles.
+func (self *Game) ResetPeriod() {
+	if self.detector == nil {
+		return
+	}
+
+	self.detector.history = map[uint64][]stateRecord{}
+	self.detector.initial = nil
+	self.detector.found = false
+	self.recordState()
+}
+
+// Runs `n` cycles. When the game is made with the option `StopOnPeriod` it stops once the
+// period is found. Returns the number of cycles run.
+func (self *Game) Run(n uint) (uint, error) {
+	for i := uint(0); i < n; i++ {
+		if d := self.detector; d != nil && d.stopOnPeriod && d.found {
+			return i, nil
+		}
+
+		if err := self.Cycle(); err != nil {
+			return i, err
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,218 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function hashUniverse is the same in all the storages.
+func TestHashUniverse(t *testing.T) {
+	assert := assert.New(t)
+	positions := []Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	hashes := []uint64{}
+
+	for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE, matrix.STORAGE_SPARSE} {
+		g, _ := NewWithOptions(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, positions, Options{Storage: storage})
+		hashes = append(hashes, hashUniverse(g.GetMatrix(), 0, 0))
+	}
+
+	assert.Equal(hashes[0], hashes[1], "The dense hash is different.")
+	assert.Equal(hashes[0], hashes[2], "The sparse hash is different.")
+
+	g, _ := New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, positions[:4])
+	assert.NotEqual(hashUniverse(g.GetMatrix(), 0, 0), hashes[0], "The hash of other state is the same.")
+
+	// The moved points.
+	g, _ = New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, []Position{{2, 1}, {3, 2}, {1, 3}, {2, 3}, {3, 3}})
+	assert.Equal(hashUniverse(g.GetMatrix(), -1, -1), hashes[0], "The hash of the moved points is different.")
+}
+
+// Test the function GetPeriod.
+func TestGetPeriod(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		name      string
+		positions []Position
+		boundary  matrix.Boundary
+		cycles    uint
+		period    uint
+		start     uint
+	}{
+		{"block", []Position{{1, 1}, {2, 1}, {1, 2}, {2, 2}}, matrix.BOUNDARY_DEAD, 1, 1, 0},
+		{"blinker", []Position{{4, 5}, {5, 5}, {6, 5}}, matrix.BOUNDARY_DEAD, 2, 2, 0},
+		{"pre-block", []Position{{1, 1}, {2, 1}, {1, 2}}, matrix.BOUNDARY_DEAD, 2, 1, 1},
+		{"glider in a torus", []Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}, matrix.BOUNDARY_TORUS, 40, 40, 0},
+		{"empty", []Position{}, matrix.BOUNDARY_DEAD, 1, 1, 0},
+	}
+
+	for _, test := range tests {
+		for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE} {
+			options := Options{Boundary: test.boundary, Storage: storage, DetectPeriod: true}
+			g, _ := NewWithOptions(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, test.positions, options)
+
+			for i := uint(1); i < test.cycles; i++ {
+				g.Cycle()
+				_, _, found := g.GetPeriod()
+				assert.Equal(found, false, fmt.Sprintf("The period of the %s is found too early.", test.name))
+			}
+
+			g.Cycle()
+			period, start, found := g.GetPeriod()
+			assert.Equal(found, true, fmt.Sprintf("The period of the %s is not found.", test.name))
+			assert.Equal(period, test.period, fmt.Sprintf("Invalid period of the %s.", test.name))
+			assert.Equal(start, test.start, fmt.Sprintf("Invalid start of the %s.", test.name))
+		}
+	}
+
+	// Without detection.
+	g, _ := New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, []Position{{4, 5}, {5, 5}, {6, 5}})
+	g.Cycle()
+	g.Cycle()
+	_, _, found := g.GetPeriod()
+	assert.Equal(found, false, "The period is detected without the option.")
+}
+
+// Test the states with the same hash are compared before finding the period.
+func TestGetPeriodHashCollision(t *testing.T) {
+	assert := assert.New(t)
+	options := Options{DetectPeriod: true}
+	g, _ := NewWithOptions(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, []Position{{4, 5}, {5, 5}, {6, 5}}, options)
+
+	// The horizontal blinker, saved in the cycle 0 with the hash of the vertical blinker.
+	vertical, _ := New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, []Position{{5, 4}, {5, 5}, {5, 6}})
+	h := hashUniverse(vertical.GetMatrix(), 0, 0)
+	g.detector.history[h] = append(g.detector.history[h], stateRecord{0, 0, 0})
+
+	g.Cycle()
+	_, _, found := g.GetPeriod()
+	assert.Equal(found, false, "The period is found with a hash collision.")
+
+	g.Cycle()
+	period, start, found := g.GetPeriod()
+	assert.Equal([3]interface{}{period, start, found}, [3]interface{}{uint(2), uint(0), true}, "Invalid period after the collision.")
+}
+
+// Test the functions GetDisplacement, IsSpaceship and GetSpeed.
+func TestSpaceship(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		name      string
+		positions []Position
+		period    uint
+		dx, dy    int
+		speed     string
+	}{
+		{"glider", []Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}, 4, 1, 1, "c/4 diagonal"},
+		{"LWSS", []Position{{1, 0}, {4, 0}, {0, 1}, {0, 2}, {4, 2}, {0, 3}, {1, 3}, {2, 3}, {3, 3}}, 4, -2, 0, "c/2 orthogonal"},
+		{"blinker", []Position{{0, 0}, {1, 0}, {2, 0}}, 2, 0, 0, ""},
+	}
+
+	for _, test := range tests {
+		options := Options{Storage: matrix.STORAGE_SPARSE, StopOnPeriod: true}
+		g, _ := NewWithOptions(0, 0, test.positions, options)
+		g.Run(100)
+
+		period, _, _ := g.GetPeriod()
+		dx, dy, found := g.GetDisplacement()
+		assert.Equal(found, true, fmt.Sprintf("The period of the %s is not found.", test.name))
+		assert.Equal(period, test.period, fmt.Sprintf("Invalid period of the %s.", test.name))
+		assert.Equal([2]int{dx, dy}, [2]int{test.dx, test.dy}, fmt.Sprintf("Invalid displacement of the %s.", test.name))
+		assert.Equal(g.IsSpaceship(), test.speed != "", fmt.Sprintf("Invalid spaceship check of the %s.", test.name))
+		assert.Equal(g.GetSpeed(), test.speed, fmt.Sprintf("Invalid speed of the %s.", test.name))
+	}
+
+	g, _ := NewWithOptions(0, 0, []Position{{0, 0}}, Options{Storage: matrix.STORAGE_SPARSE})
+	_, _, found := g.GetDisplacement()
+	assert.Equal(found, false, "The displacement is found without detection.")
+	assert.Equal(g.GetSpeed(), "", "The speed is not empty without detection.")
+}
+
+// Test the spaceships are not detected in the bounded universes: the edges change them.
+func TestSpaceshipBounded(t *testing.T) {
+	assert := assert.New(t)
+	glider := []Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+
+	for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_DENSE} {
+		options := Options{Storage: storage, StopOnPeriod: true}
+		g, _ := NewWithOptions(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, glider, options)
+		g.Run(100)
+
+		// The glider becomes a block in the corner.
+		period, _, found := g.GetPeriod()
+		assert.Equal(found, true, fmt.Sprintf("The period is not found (%s).", storage))
+		assert.Equal(period, uint(1), fmt.Sprintf("Invalid period (%s).", storage))
+		assert.Equal(g.IsSpaceship(), false, fmt.Sprintf("The glider is a spaceship (%s).", storage))
+		assert.Equal(g.GetMatrix().GetPointsEnabled(), 4, fmt.Sprintf("The glider is not a block (%s).", storage))
+	}
+}
+
+// Test the function FormatSpeed.
+func TestFormatSpeed(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		dx, dy int
+		period uint
+		speed  string
+	}{
+		{1, 1, 4, "c/4 diagonal"},
+		{-2, 0, 4, "c/2 orthogonal"},
+		{0, 2, 5, "2c/5 orthogonal"},
+		{1, 0, 1, "c orthogonal"},
+		{-3, 3, 12, "c/4 diagonal"},
+		{2, -1, 6, "(2,1)c/6 oblique"},
+		{1, 2, 6, "(2,1)c/6 oblique"},
+		{0, 0, 2, ""},
+	}
+
+	for _, test := range tests {
+		speed := FormatSpeed(test.dx, test.dy, test.period)
+		assert.Equal(speed, test.speed, fmt.Sprintf("Invalid speed of (%d, %d) in %d cycles.", test.dx, test.dy, test.period))
+	}
+}
+
+// Test the function ResetPeriod.
+func TestResetPeriod(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewWithOptions(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE,
+		[]Position{{1, 1}, {2, 1}, {1, 2}, {2, 2}}, Options{DetectPeriod: true})
+
+	g.Cycle()
+	_, _, found := g.GetPeriod()
+	assert.Equal(found, true, "The period of the block is not found.")
+
+	// The block becomes a blinker.
+	g.GetMatrix().Reset()
+	g.GetMatrix().EnablePoint(4, 5)
+	g.GetMatrix().EnablePoint(5, 5)
+	g.GetMatrix().EnablePoint(6, 5)
+	g.ResetPeriod()
+
+	g.Cycle()
+	_, _, found = g.GetPeriod()
+	assert.Equal(found, false, "The period is found after the reset.")
+
+	g.Cycle()
+	period, start, found := g.GetPeriod()
+	assert.Equal([3]interface{}{period, start, found}, [3]interface{}{uint(2), uint(1), true}, "Invalid period after the reset.")
+}
+
+// Test the function Run.
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+	blinker := []Position{{4, 5}, {5, 5}, {6, 5}}
+
+	g, _ := NewWithOptions(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, blinker, Options{StopOnPeriod: true})
+	n, err := g.Run(100)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(n, uint(2), "The run has not stopped.")
+	assert.Equal(g.GetCyclesNum(), uint(2), "Invalid cycles.")
+
+	g, _ = NewWithOptions(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, blinker, Options{DetectPeriod: true})
+	n, _ = g.Run(100)
+	assert.Equal(n, uint(100), "The run has stopped.")
+	period, _, _ := g.GetPeriod()
+	assert.Equal(period, uint(2), "Invalid period.")
+}
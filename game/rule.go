@@ -0,0 +1,364 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Rulestring of the Conway's game of life.
+const CONWAY_RULE string = "B3/S23"
+
+// Maximum number of adjacents of a point.
+const MAX_ADJACENTS int = 8
+
+// Maximum number of adjacents of the conditions written as lists of numbers.
+const MAX_CONDITION int = 9999
+
+// Maximum number of states of the Generations rules.
+const MAX_STATES int = 256
+
+// Birth and survival conditions of a Life-like game, or of a Generations game, where the
+// points enabled that do not survive pass through dying states before getting disabled.
+// The conditions can depend on the configuration of the adjacents, see hensel.go.
+type Rule struct {
+	// `birth[n]` is true when a disabled point with `n` adjacents gets enabled, in some
+	// configuration of the adjacents. It has `MAX_ADJACENTS` + 1 items at least.
+	birth []bool
+
+	// `survival[n]` is true when an enabled point with `n` adjacents stays enabled, in some
+	// configuration of the adjacents. It has `MAX_ADJACENTS` + 1 items at least.
+	survival []bool
+
+	// `birthMasks[m]` is true when a disabled point with the adjacents of the mask `m` enabled
+	// gets enabled.
+	birthMasks [256]bool
+
+	// `survivalMasks[m]` is true when an enabled point with the adjacents of the mask `m`
+	// enabled stays enabled.
+	survivalMasks [256]bool
+
+	// Whether the conditions only depend on the number of adjacents.
+	totalistic bool
+
+	// Neighbourhood and conditions of the Larger than Life rules. Nil in the other rules.
+	ltl *ltlRule
+
+	// Number of states of the points: disabled, enabled and the dying states.
+	// The Life-like rules have 2 states.
+	states int
+}
+
+// Returns whether the character `c` is a letter of the Hensel notation or the negation.
+func isHenselCharacter(c byte) bool {
+	c |= 0x20 // lower case.
+	return c == '-' || (c >= 'a' && c <= 'z')
+}
+
+// Parses the numbers separated by ',' of the rulestring part `part`, used for the
+// neighbourhoods with more than `MAX_ADJACENTS` adjacents, and stores them in `conditions`.
+// The configurations of adjacents of the numbers are stored in `masks`.
+func parseRuleNumbers(rule, part string, conditions *[]bool, masks *[256]bool) error {
+	for _, item := range strings.Split(part, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n < 0 || n > MAX_CONDITION {
+			return InvalidRuleError(rule, fmt.Sprintf("invalid number '%s'", item))
+		}
+
+		for len(*conditions) <= n {
+			*conditions = append(*conditions, false)
+		}
+
+		if (*conditions)[n] {
+			return InvalidRuleError(rule, fmt.Sprintf("the number '%d' is repeated", n))
+		}
+
+		(*conditions)[n] = true
+		if n <= MAX_ADJACENTS {
+			parseHenselLetters(rule, n, "", masks)
+		}
+	}
+
+	return nil
+}
+
+// Parses the digits of the rulestring part `part` and stores them in `conditions`, and their
+// configurations of adjacents in `masks`. `letters` indicates if the digits can have letters
+// of the Hensel notation. `rule` is the full rulestring, used in the errors.
+// The parts with ',' are lists of numbers, see `parseRuleNumbers`.
+func parseRuleDigits(rule, part string, letters bool, conditions *[]bool, masks *[256]bool) error {
+	if strings.Contains(part, ",") {
+		return parseRuleNumbers(rule, part, conditions, masks)
+	}
+
+	for i := 0; i < len(part); i++ {
+		c := part[i]
+		if c < '0' || c > '0'+byte(MAX_ADJACENTS) {
+			return InvalidRuleError(rule, fmt.Sprintf("invalid character '%c'", c))
+		}
+
+		n := int(c - '0')
+		if (*conditions)[n] {
+			return InvalidRuleError(rule, fmt.Sprintf("the digit '%c' is repeated", c))
+		}
+
+		end := i + 1
+		for letters && end < len(part) && isHenselCharacter(part[end]) {
+			end++
+		}
+
+		if err := parseHenselLetters(rule, n, part[i+1:end], masks); err != nil {
+			return err
+		}
+
+		(*conditions)[n] = true
+		i = end - 1
+	}
+
+	return nil
+}
+
+// Returns whether the conditions of the masks `masks` only depend on the number of adjacents.
+func isTotalistic(masks *[256]bool) bool {
+	for mask := range masks {
+		// All the configurations of the same number of adjacents are equal to the first one.
+		first := uint8(0xff) >> uint(MAX_ADJACENTS-countMask(uint8(mask)))
+		if masks[mask] != masks[first] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Parses the part `part` with the number of states of the rulestring `rule`.
+// `prefixed` indicates if the number needs the prefix 'C'.
+func parseRuleStates(rule, part string, prefixed bool) (int, error) {
+	if len(part) > 0 && part[0]|0x20 == 'c' {
+		part = part[1:]
+	} else if prefixed {
+		return 0, InvalidRuleError(rule, "the number of states needs the prefix 'C'")
+	}
+
+	states, err := strconv.Atoi(part)
+	if err != nil || states < 2 || states > MAX_STATES {
+		return 0, InvalidRuleError(rule, fmt.Sprintf("the number of states must be from 2 to %d", MAX_STATES))
+	}
+
+	return states, nil
+}
+
+// Parses the rulestring `rule` and returns the rule.
+// It accepts the B/S notation ("B36/S23") and the S/B notation ("23/36"),
+// letters are case insensitive and the B/S parts can be in any order.
+// The Generations rules have a third part with the number of states: "B2/S/C3" or "/2/3".
+// The Larger than Life rules start with the radius, see `parseLtLRule`.
+// Returns an error whether the rulestring is malformed.
+func ParseRule(rule string) (*Rule, error) {
+	r := &Rule{states: 2, birth: make([]bool, MAX_ADJACENTS+1), survival: make([]bool, MAX_ADJACENTS+1)}
+	trimmed := strings.TrimSpace(rule)
+	if len(trimmed) > 0 && trimmed[0]|0x20 == 'r' {
+		return parseLtLRule(rule)
+	}
+
+	parts := strings.Split(trimmed, "/")
+
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, InvalidRuleError(rule, "it must have two or three parts separated by '/'")
+	}
+
+	prefixes := [2]byte{}
+	for i, part := range parts[:2] {
+		if len(part) > 0 {
+			prefixes[i] = part[0] | 0x20 // lower case.
+		}
+	}
+
+	switch {
+	case prefixes[0] == 'b' && prefixes[1] == 's':
+		// B/S notation.
+	case prefixes[0] == 's' && prefixes[1] == 'b':
+		// B/S notation in reverse order.
+		parts[0], parts[1] = parts[1], parts[0]
+	case (prefixes[0] == 'b' || prefixes[0] == 's') || (prefixes[1] == 'b' || prefixes[1] == 's'):
+		return nil, InvalidRuleError(rule, "it needs a 'B' part and a 'S' part")
+	default:
+		// S/B notation: survival first.
+		parts[0], parts[1] = "b"+parts[1], "s"+parts[0]
+	}
+
+	if len(parts) == 3 {
+		var err error
+		prefixed := prefixes[0] == 'b' || prefixes[0] == 's'
+		if r.states, err = parseRuleStates(rule, parts[2], prefixed); err != nil {
+			return nil, err
+		}
+	}
+
+	// The letters of the Hensel notation are only allowed in the B/S notation.
+	letters := prefixes[0] == 'b' || prefixes[0] == 's'
+
+	if err := parseRuleDigits(rule, parts[0][1:], letters, &r.birth, &r.birthMasks); err != nil {
+		return nil, err
+	}
+
+	if err := parseRuleDigits(rule, parts[1][1:], letters, &r.survival, &r.survivalMasks); err != nil {
+		return nil, err
+	}
+
+	r.totalistic = isTotalistic(&r.birthMasks) && isTotalistic(&r.survivalMasks)
+
+	return r, nil
+}
+
+// Same as `ParseRule` but it panics whether the rulestring is invalid.
+// Used for define rules in constants.
+func MustParseRule(rule string) *Rule {
+	r, err := ParseRule(rule)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Returns true when a disabled point with `adj` adjacents gets enabled.
+// In the non-totalistic rules, it is true when it gets enabled in some configuration of the
+// adjacents. See `IsBirthMask`.
+func (self *Rule) IsBirth(adj int) bool {
+	if self.ltl != nil {
+		return adj >= self.ltl.birth[0] && adj <= self.ltl.birth[1]
+	}
+
+	return adj >= 0 && adj < len(self.birth) && self.birth[adj]
+}
+
+// Returns true when an enabled point with `adj` adjacents stays enabled.
+// In the non-totalistic rules, it is true when it stays enabled in some configuration of the
+// adjacents. See `IsSurvivalMask`.
+func (self *Rule) IsSurvival(adj int) bool {
+	if self.ltl != nil {
+		return adj >= self.ltl.survival[0] && adj <= self.ltl.survival[1]
+	}
+
+	return adj >= 0 && adj < len(self.survival) && self.survival[adj]
+}
+
+// Returns true when a disabled point with the adjacents of the mask `mask` enabled gets
+// enabled. See `GetAdjacentsMask`.
+func (self *Rule) IsBirthMask(mask uint8) bool {
+	return self.birthMasks[mask]
+}
+
+// Returns true when an enabled point with the adjacents of the mask `mask` enabled stays
+// enabled. See `GetAdjacentsMask`.
+func (self *Rule) IsSurvivalMask(mask uint8) bool {
+	return self.survivalMasks[mask]
+}
+
+// Returns true when the conditions of the rule only depend on the number of adjacents.
+func (self *Rule) IsTotalistic() bool {
+	return self.totalistic
+}
+
+// Returns true when a point with `adj` adjacents is enabled in the next cycle.
+// `enabled` indicates if the point is enabled in the current cycle.
+func (self *Rule) NextState(enabled bool, adj int) bool {
+	if enabled {
+		return self.IsSurvival(adj)
+	}
+
+	return self.IsBirth(adj)
+}
+
+// Same as `NextState` but using the mask `mask` with the adjacents enabled.
+func (self *Rule) NextStateMask(enabled bool, mask uint8) bool {
+	if enabled {
+		return self.IsSurvivalMask(mask)
+	}
+
+	return self.IsBirthMask(mask)
+}
+
+// Returns the number of states of the points. The Life-like rules have 2 states.
+func (self *Rule) GetStates() int {
+	return self.states
+}
+
+// Returns the value of a point in the next cycle, using the value of the point `value` in the
+// current cycle and the number of adjacents enabled `adj`.
+// The values are the values of the matrices: a point enabled that does not survive gets the
+// first dying state, or gets disabled when the rule has not dying states, and the dying states
+// pass to the next one until the point gets disabled.
+func (self *Rule) NextValue(value, adj int) int {
+	return self.nextValue(value, self.IsBirth(adj), self.IsSurvival(adj))
+}
+
+// Same as `NextValue` but using the mask `mask` with the adjacents enabled.
+func (self *Rule) NextValueMask(value int, mask uint8) int {
+	return self.nextValue(value, self.IsBirthMask(mask), self.IsSurvivalMask(mask))
+}
+
+// Returns the value of a point in the next cycle, using the value of the point `value` in the
+// current cycle. `born` and `survives` are the conditions of the rule with its adjacents.
+func (self *Rule) nextValue(value int, born, survives bool) int {
+	switch {
+	case value == matrix.MATRIX_POINT_DISABLED:
+		if born {
+			return matrix.MATRIX_POINT_ENABLED
+		}
+
+		return matrix.MATRIX_POINT_DISABLED
+	case value == matrix.MATRIX_POINT_ENABLED && survives:
+		return matrix.MATRIX_POINT_ENABLED
+	case value+1 >= self.states:
+		return matrix.MATRIX_POINT_DISABLED
+	default:
+		return value + 1
+	}
+}
+
+// Returns the conditions `conditions`, with the configurations of adjacents `masks`, in the
+// rulestring: digits with the letters of the Hensel notation, or numbers separated by ','
+// when there are numbers greater than `MAX_ADJACENTS`.
+func formatConditions(conditions []bool, masks *[256]bool) string {
+	numbers, digits, big := []string{}, "", false
+
+	for n, enabled := range conditions {
+		if !enabled {
+			continue
+		}
+
+		numbers = append(numbers, strconv.Itoa(n))
+		if n > MAX_ADJACENTS {
+			big = true
+		} else {
+			digits += strconv.Itoa(n) + formatHenselLetters(n, masks)
+		}
+	}
+
+	if big {
+		return strings.Join(numbers, ",")
+	}
+
+	return digits
+}
+
+// Returns the rulestring in canonical B/S notation. Example: "B36/S23".
+// The non-totalistic rules have the letters of the Hensel notation. Example: "B2-a/S12".
+// The Generations rules have the number of states at the end. Example: "B2/S/C3".
+func (self Rule) String() string {
+	if self.ltl != nil {
+		return self.ltl.format(self.states)
+	}
+
+	rule := "B" + formatConditions(self.birth, &self.birthMasks)
+	rule += "/S" + formatConditions(self.survival, &self.survivalMasks)
+	if self.states > 2 {
+		rule += fmt.Sprintf("/C%d", self.states)
+	}
+
+	return rule
+}
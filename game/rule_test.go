@@ -0,0 +1,199 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function ParseRule with valid rulestrings.
+func TestParseRule(t *testing.T) {
+	assert := assert.New(t)
+	rules := map[string]string{
+		"B3/S23":          "B3/S23",
+		"b3/s23":          "B3/S23",
+		"S23/B3":          "B3/S23",
+		"23/3":            "B3/S23",
+		"B36/S23":         "B36/S23",
+		"23/36":           "B36/S23",
+		"B3678/S34678":    "B3678/S34678",
+		"B2/S":            "B2/S",
+		"/2":              "B2/S",
+		"B63/S32":         "B36/S23",
+		" B3/S23 ":        "B3/S23",
+		"B012345678/S012": "B012345678/S012",
+		"B2/S/C3":         "B2/S/C3",
+		"b2/s/c3":         "B2/S/C3",
+		"/2/3":            "B2/S/C3",
+		"345/2/4":         "B2/S345/C4",
+		"S345/B2/C4":      "B2/S345/C4",
+		"B3/S23/C2":       "B3/S23",
+		// Each part uses the comma list only when it has a number greater than 8, so the
+		// part S2,3 is written with digits although the part B3,10 keeps the commas.
+		"B3,10/S2,3": "B3,10/S23",
+		"B3,6/S2,3":  "B36/S23",
+		"S12,2/B9,1": "B1,9/S2,12",
+	}
+
+	for rulestring, canonical := range rules {
+		rule, err := ParseRule(rulestring)
+		assert.Equal(err, nil, fmt.Sprintf("There is an error parsing %s.", rulestring))
+		assert.Equal(rule.String(), canonical, fmt.Sprintf("Invalid canonical rule of %s.", rulestring))
+	}
+}
+
+// Test the errors of the function ParseRule.
+func TestParseRuleError(t *testing.T) {
+	assert := assert.New(t)
+	rules := map[string]string{
+		"":        "it must have two or three parts separated by '/'",
+		"B3S23":   "it must have two or three parts separated by '/'",
+		"/2/3/4":  "it must have two or three parts separated by '/'",
+		"B3/S2/3": "the number of states needs the prefix 'C'",
+		"B2/S/C1": "the number of states must be from 2 to 256",
+		"B2/S/Cx": "the number of states must be from 2 to 256",
+		"/2/257":  "the number of states must be from 2 to 256",
+		"B3/B23":  "it needs a 'B' part and a 'S' part",
+		"B3/23":   "it needs a 'B' part and a 'S' part",
+		"B9/S23":  "invalid character '9'",
+		"B3/S2x":  "invalid character 'x'",
+		"23/3a":   "invalid character 'a'",
+		"B33/S23": "the digit '3' is repeated",
+		"B3/S232": "the digit '2' is repeated",
+		"X3/23":   "invalid character 'X'",
+		"B3,x/S":  "invalid number 'x'",
+		"B3,/S":   "invalid number ''",
+		"B3,3/S":  "the number '3' is repeated",
+	}
+
+	for rulestring, reason := range rules {
+		rule, err := ParseRule(rulestring)
+		assert.Equal(rule, (*Rule)(nil), fmt.Sprintf("The rule %s is not nil.", rulestring))
+		assert.Equal(err, InvalidRuleError(rulestring, reason), "The error does not match.")
+	}
+}
+
+// Test the functions IsBirth and IsSurvival.
+func TestRuleConditions(t *testing.T) {
+	assert := assert.New(t)
+	rule := MustParseRule("B36/S23")
+
+	for adj := -1; adj <= MAX_ADJACENTS+1; adj++ {
+		birth := adj == 3 || adj == 6
+		survival := adj == 2 || adj == 3
+		assert.Equal(rule.IsBirth(adj), birth, fmt.Sprintf("Invalid birth with %d adjacents.", adj))
+		assert.Equal(rule.IsSurvival(adj), survival, fmt.Sprintf("Invalid survival with %d adjacents.", adj))
+	}
+}
+
+// Test the game uses the Conway's rule by default.
+func TestNewGameDefaultRule(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := New(min, min, []Position{})
+	assert.Equal(g.GetRule().String(), CONWAY_RULE, "The default rule is not the Conway's rule.")
+}
+
+// Test the function `game.Cycle` using the HighLife rule (B36/S23).
+// In HighLife a disabled point with 6 adjacents gets enabled.
+func TestCycleFuncHighLife(t *testing.T) {
+	assert := assert.New(t)
+	initial := []Position{{4, 4}, {5, 4}, {6, 4}, {4, 6}, {5, 6}, {6, 6}}
+
+	// Conway: the center point does not get enabled.
+	g, _ := NewWithRule(min, min, initial, MustParseRule(CONWAY_RULE))
+	g.Cycle()
+	enabled, _ := g.GetMatrix().IsEnabled(5, 5)
+	assert.Equal(enabled, false, "The point 5x5 is enabled.")
+
+	// HighLife: the center point gets enabled.
+	g, _ = NewWithRule(min, min, initial, MustParseRule("B36/S23"))
+	g.Cycle()
+	enabled, _ = g.GetMatrix().IsEnabled(5, 5)
+	assert.Equal(enabled, true, "The point 5x5 is disabled.")
+}
+
+// Test the function `game.Cycle` using the Seeds rule (B2/S).
+// In Seeds all the enabled points get disabled in each cycle.
+func TestCycleFuncSeeds(t *testing.T) {
+	assert := assert.New(t)
+	g, _ := NewWithRule(min, min, []Position{{4, 4}, {4, 5}}, MustParseRule("B2/S"))
+	positions := map[Position]bool{{3, 4}: true, {3, 5}: true, {5, 4}: true, {5, 5}: true}
+
+	err := g.Cycle()
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(g.GetMatrix().GetPointsEnabled(), len(positions), "Invalid points enabled.")
+
+	for p := range positions {
+		enabled, _ := g.GetMatrix().IsEnabled(p[0], p[1])
+		assert.Equal(enabled, true, fmt.Sprintf("The point %dx%d is disabled.", p[0], p[1]))
+	}
+}
+
+// Test the function NextValue.
+func TestRuleNextValue(t *testing.T) {
+	assert := assert.New(t)
+	conway := MustParseRule(CONWAY_RULE)
+	brain := MustParseRule("B2/S/C3")
+
+	assert.Equal(conway.GetStates(), 2, "Invalid states of the Conway's rule.")
+	assert.Equal(brain.GetStates(), 3, "Invalid states of the Brian's Brain rule.")
+
+	assert.Equal(conway.NextValue(disabled, 3), enabled, "The point is not born.")
+	assert.Equal(conway.NextValue(enabled, 2), enabled, "The point does not survive.")
+	assert.Equal(conway.NextValue(enabled, 1), disabled, "The point does not die.")
+
+	assert.Equal(brain.NextValue(disabled, 2), enabled, "The point is not born in Brian's Brain.")
+	assert.Equal(brain.NextValue(disabled, 3), disabled, "The point is born in Brian's Brain.")
+	assert.Equal(brain.NextValue(enabled, 2), matrix.MATRIX_POINT_DYING, "The point is not dying.")
+	assert.Equal(brain.NextValue(matrix.MATRIX_POINT_DYING, 2), disabled, "The dying point is not disabled.")
+
+	// Star Wars: the dying points pass through 2 dying states.
+	starWars := MustParseRule("345/2/4")
+	assert.Equal(starWars.NextValue(enabled, 3), enabled, "The point does not survive in Star Wars.")
+	assert.Equal(starWars.NextValue(enabled, 2), 2, "Invalid first dying state.")
+	assert.Equal(starWars.NextValue(2, 2), 3, "Invalid second dying state.")
+	assert.Equal(starWars.NextValue(3, 3), disabled, "The last dying state is not disabled.")
+}
+
+// Test the function `game.Cycle` using the Brian's Brain rule (B2/S/C3).
+// In Brian's Brain all the enabled points get dying in each cycle, and disabled in the next one.
+func TestCycleFuncBriansBrain(t *testing.T) {
+	assert := assert.New(t)
+	options := Options{Rule: MustParseRule("B2/S/C3"), Storage: matrix.STORAGE_DENSE}
+	g, err := NewWithOptions(min, min, []Position{{4, 4}, {4, 5}}, options)
+	assert.Equal(err, nil, "There is an error.")
+
+	m := g.GetMatrix().(*matrix.Matrix)
+	g.Cycle()
+	assert.Equal(m.GetPointsEnabled(), 4, "Invalid points enabled in the first cycle.")
+	assert.Equal(m.GetPointsWithValue(matrix.MATRIX_POINT_DYING), 2, "Invalid dying points in the first cycle.")
+
+	value, _ := m.GetPoint(4, 4)
+	assert.Equal(value, matrix.MATRIX_POINT_DYING, "The point 4x4 is not dying.")
+
+	// The dying points do not count as adjacents.
+	g.Cycle()
+	assert.Equal(m.GetPointsEnabled(), 6, "Invalid points enabled in the second cycle.")
+	assert.Equal(m.GetPointsWithValue(matrix.MATRIX_POINT_DYING), 4, "Invalid dying points in the second cycle.")
+
+	value, _ = m.GetPoint(4, 4)
+	assert.Equal(value, disabled, "The point 4x4 is not disabled.")
+
+	for _, storage := range []matrix.Storage{matrix.STORAGE_PACKED, matrix.STORAGE_SPARSE} {
+		options.Storage = storage
+		_, err = NewWithOptions(min, min, []Position{}, options)
+		reason := "the Generations rules need the dense storage"
+		assert.Equal(err, InvalidRuleError("B2/S/C3", reason), "The error does not match.")
+	}
+
+	// The dense storage is used by default.
+	g, err = NewWithRule(min, min, []Position{{4, 4}, {4, 5}}, options.Rule)
+	assert.Equal(err, nil, "There is an error with the default storage.")
+	_, dense := g.GetMatrix().(*matrix.Matrix)
+	assert.Equal(dense, true, "The default storage is not dense.")
+
+	g.Cycle()
+	assert.Equal(g.GetMatrix().(*matrix.Matrix).GetPointsWithValue(matrix.MATRIX_POINT_DYING), 2, "Invalid dying points with the default storage.")
+}
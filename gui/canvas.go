@@ -2,82 +2,93 @@ package gui
 
 import (
 	"github.com/davidnotplay/gameoflife/game"
-	"github.com/gopherjs/gopherjs/js"
-	"math"
+	"github.com/davidnotplay/gameoflife/matrix"
 	"time"
 )
 
 // Constant pixels per points.
 const ppp int = 14
 
-// Canvas color cells whe these are enabled or disabled.
-var pointColors map[bool]string = map[bool]string{false: "#666666", true: "#ffeb3b"}
+// Default time between two game cycles.
+const DEFAULT_DELAY time.Duration = 200 * time.Millisecond
 
 type Canvas struct {
-	// Js canvas object.
-	canvas *js.Object
+	// Surface where the game is drawn.
+	renderer Renderer
 
 	// Game matrix.
 	game *game.Game
 
 	playing bool
-}
 
-// Get the canvas js object from html dom.
-func getCanvas() *js.Object {
-	return js.Global.Get("document").Call("getElementById", "game-of-life")
+	// Time between two game cycles.
+	delay time.Duration
 }
 
-// Get the game size (width, height) depending of the window browser size.
-func getGameSize() (int, int) {
-	canvas := js.Global.Get("document").Call("getElementById", "game-of-life")
-
-	// Set the canvas size using the windows size.
-	ww := js.Global.Get("window").Get("innerWidth").Float()
-	wh := js.Global.Get("window").Get("innerHeight").Float()
-	canvas.Set("width", ww)
-	canvas.Set("height", wh)
-
-	// Generate the matrix size using the window size.
-	gw := int(math.Ceil(ww / float64(ppp)))
-	gh := int(math.Ceil(wh / float64(ppp)))
+// Get the game size (width, height) depending of the renderer size and the topology `topology`.
+func getGameSize(r Renderer, topology matrix.Topology) (int, int) {
+	w, h := r.GetSize()
 
-	return gw, gh
+	// Generate the matrix size using the renderer size.
+	return getTopologySize(topology, w, h)
 }
 
 // Make the canvas using the matrix data.
 func (self *Canvas) generate() error {
-	matrix := self.game.GetMatrix()
-	w, h := matrix.GetWidth(), matrix.GetHeight()
-	ctx := self.canvas.Call("getContext", "2d")
+	m := self.game.GetMatrix()
+	w, h := m.GetWidth(), m.GetHeight()
+	topology := self.game.GetTopology()
+
+	if err := self.renderer.Clear(); err != nil {
+		return err
+	}
 
 	for i := 0; i < w; i++ {
 		for j := 0; j < h; j++ {
-			enabled, err := matrix.IsEnabled(i, j)
+			enabled, err := m.IsEnabled(i, j)
 			if err != nil {
 				return err
 			}
 
-			ctx.Set("fillStyle", pointColors[enabled])
-			ctx.Call("fillRect", i*ppp+1, j*ppp+1, ppp-1, ppp-1)
+			if topology != matrix.TOPOLOGY_SQUARE {
+				err = self.renderer.DrawPolygon(getCellPolygon(topology, i, j), enabled)
+			} else {
+				err = self.renderer.DrawCell(i*ppp, j*ppp, ppp, enabled)
+			}
+
+			if err != nil {
+				return err
+			}
 		}
 	}
 
-	return nil
+	return self.renderer.Present()
 }
 
 // Make new Canvas for the game.
-// The function make the html5 canvas and prepare the game using the initial positions `p`
-func NewCanvas(p *[]game.Position) (*Canvas, error) {
-	gw, gh := getGameSize()
-	game, err := game.New(gw, gh, *p)
+// The function draws the game in the renderer `r` using the initial positions `p`.
+func NewCanvas(r Renderer, p *[]game.Position) (*Canvas, error) {
+	return NewCanvasWithTopology(r, p, matrix.TOPOLOGY_SQUARE)
+}
+
+// Same as `NewCanvas` but the game uses the topology `topology`, and the cells are drawn
+// as hexagons or triangles.
+func NewCanvasWithTopology(r Renderer, p *[]game.Position, topology matrix.Topology) (*Canvas, error) {
+	options := game.Options{Topology: topology}
+	if topology != matrix.TOPOLOGY_SQUARE {
+		// The other topologies are only stored in the dense matrix.
+		options.Storage = matrix.STORAGE_DENSE
+	}
+
+	gw, gh := getGameSize(r, topology)
+	game, err := game.NewWithOptions(gw, gh, *p, options)
 
 	if err != nil {
 		// Error creating the game.
 		return nil, err
 	}
 
-	canvas := &Canvas{getCanvas(), game, false}
+	canvas := &Canvas{r, game, false, DEFAULT_DELAY}
 	err = canvas.generate()
 
 	if err != nil {
@@ -92,7 +103,7 @@ func (self *Canvas) run(callback func(canvas *Canvas)) error {
 	var err error
 
 	for self.playing {
-		time.Sleep(200 * time.Millisecond)
+		time.Sleep(self.delay)
 		err = self.game.Cycle()
 
 		if err != nil {
@@ -120,15 +131,24 @@ func (self *Canvas) Stop() {
 	self.playing = false
 }
 
-// Returns the js object where are saved the canvas.
-func (self *Canvas) GetJsCanvas() *js.Object {
-	return self.canvas
+// Sets the time between two game cycles.
+func (self *Canvas) SetDelay(delay time.Duration) {
+	self.delay = delay
+}
+
+// Returns the renderer where the game is drawn.
+func (self *Canvas) GetRenderer() Renderer {
+	return self.renderer
+}
+
+// Returns the game drawn.
+func (self *Canvas) GetGame() *game.Game {
+	return self.game
 }
 
 func (self *Canvas) ToggleMatrixPoint(x, y int) error {
 	var err error = nil
-	mx := x / ppp
-	my := y / ppp
+	mx, my := getCellPosition(self.game.GetTopology(), x, y)
 	m := self.game.GetMatrix()
 	enabled, err := m.IsEnabled(mx, my)
 
@@ -0,0 +1,144 @@
+package gui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a new canvas of 10x12 points drawn in a recording renderer.
+// The last points are partially in the renderer.
+func auxCanvas(positions ...game.Position) (*Canvas, *RecordingRenderer) {
+	r := NewRecordingRenderer(10*ppp-5, 12*ppp)
+	c, _ := NewCanvas(r, &positions)
+	c.SetDelay(time.Millisecond)
+	return c, r
+}
+
+// Test the function NewCanvas.
+func TestNewCanvas(t *testing.T) {
+	assert := assert.New(t)
+	c, r := auxCanvas(game.Position{1, 2})
+
+	m := c.GetGame().GetMatrix()
+	assert.Equal([2]int{m.GetWidth(), m.GetHeight()}, [2]int{10, 12}, "Invalid game size.")
+	assert.Equal(c.GetRenderer(), Renderer(r), "Invalid renderer.")
+	assert.Equal(r.GetFramesNum(), 1, "The canvas is not drawn.")
+	assert.Equal(r.GetCellsNum(), 10*12, "Invalid number of cells.")
+	assert.Equal(r.GetCellsEnabledNum(), 1, "Invalid number of cells enabled.")
+	assert.Equal(r.IsCellEnabled(1*ppp, 2*ppp), true, "The cell is not enabled.")
+
+	// The renderer is too small.
+	_, err := NewCanvas(NewRecordingRenderer(ppp, ppp), &[]game.Position{})
+	assert.NotEqual(err, nil, "There is not error.")
+}
+
+// Test the function ToggleMatrixPoint.
+func TestToggleMatrixPoint(t *testing.T) {
+	assert := assert.New(t)
+	c, r := auxCanvas()
+
+	// Any pixel of the cell toggles its point.
+	assert.Equal(c.ToggleMatrixPoint(3*ppp+ppp-1, 4*ppp), nil, "There is an error.")
+	enabled, _ := c.GetGame().GetMatrix().IsEnabled(3, 4)
+	assert.Equal(enabled, true, "The point is disabled.")
+	assert.Equal(r.IsCellEnabled(3*ppp, 4*ppp), true, "The cell is not redrawn.")
+	assert.Equal(r.GetFramesNum(), 2, "Invalid number of frames.")
+
+	c.ToggleMatrixPoint(3*ppp, 4*ppp)
+	enabled, _ = c.GetGame().GetMatrix().IsEnabled(3, 4)
+	assert.Equal(enabled, false, "The point is enabled.")
+	assert.Equal(r.GetCellsEnabledNum(), 0, "Invalid number of cells enabled.")
+
+	// Click outside of the matrix.
+	assert.NotEqual(c.ToggleMatrixPoint(10*ppp, 0), nil, "There is not error.")
+	assert.Equal(r.GetFramesNum(), 3, "Invalid number of frames after an error.")
+}
+
+// Test the canvas with the hexagonal and the triangular topologies.
+func TestCanvasTopology(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, topology := range []matrix.Topology{matrix.TOPOLOGY_HEXAGONAL, matrix.TOPOLOGY_TRIANGULAR} {
+		r := NewRecordingRenderer(40*ppp, 20*ppp)
+		c, err := NewCanvasWithTopology(r, &[]game.Position{{2, 3}}, topology)
+		assert.Equal(err, nil, fmt.Sprintf("There is an error with the topology %s.", topology))
+		assert.Equal(c.GetGame().GetTopology(), topology, "Invalid topology of the game.")
+
+		gw, gh := getTopologySize(topology, 40*ppp, 20*ppp)
+		m := c.GetGame().GetMatrix()
+		assert.Equal([2]int{m.GetWidth(), m.GetHeight()}, [2]int{gw, gh}, "Invalid game size.")
+		assert.Equal(r.GetCellsNum(), gw*gh, fmt.Sprintf("Invalid number of cells with the topology %s.", topology))
+		assert.Equal(r.GetCellsEnabledNum(), 1, "Invalid number of cells enabled.")
+
+		// The click in the center of the cell toggles its point.
+		x, y := auxPolygonCenter(getCellPolygon(topology, 5, 6))
+		assert.Equal(c.ToggleMatrixPoint(x, y), nil, "There is an error.")
+		enabled, _ := m.IsEnabled(5, 6)
+		assert.Equal(enabled, true, fmt.Sprintf("The point is disabled with the topology %s.", topology))
+		assert.Equal(r.GetCellsEnabledNum(), 2, "The cell is not redrawn.")
+	}
+}
+
+// Test the functions Start and Stop.
+func TestStartStop(t *testing.T) {
+	assert := assert.New(t)
+	// Blinker.
+	c, r := auxCanvas(game.Position{4, 5}, game.Position{5, 5}, game.Position{6, 5})
+	calls := 0
+
+	err := c.Start(func(canvas *Canvas) {
+		calls++
+		assert.Equal(canvas.IsPlaying(), true, "The canvas is not playing.")
+
+		if calls == 3 {
+			canvas.Stop()
+		}
+	})
+
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(c.IsPlaying(), false, "The canvas is playing.")
+	assert.Equal(c.GetGame().GetCyclesNum(), uint(3), "Invalid number of cycles.")
+	assert.Equal(r.GetFramesNum(), 4, "Invalid number of frames.")
+	assert.Equal(r.GetClearsNum(), 4, "Invalid number of clears.")
+
+	// After an odd number of cycles the blinker is vertical.
+	for y := 4; y <= 6; y++ {
+		assert.Equal(r.IsCellEnabled(5*ppp, y*ppp), true, fmt.Sprintf("The cell 5x%d is not enabled.", y))
+	}
+	assert.Equal(r.GetCellsEnabledNum(), 3, "Invalid number of cells enabled.")
+}
+
+// Test the canvas with the events of an input source.
+func TestInputSource(t *testing.T) {
+	assert := assert.New(t)
+	c, r := auxCanvas()
+	input := &ManualInput{}
+	var source InputSource = input
+
+	source.OnClick(func(x, y int) {
+		c.ToggleMatrixPoint(x, y)
+	})
+
+	keys := []rune{}
+	source.OnKey(func(key rune) {
+		keys = append(keys, key)
+	})
+
+	resized := false
+	source.OnResize(func() {
+		resized = true
+	})
+
+	input.Click(2*ppp, 2*ppp)
+	input.Key(' ')
+	input.Resize()
+
+	assert.Equal(r.IsCellEnabled(2*ppp, 2*ppp), true, "The click has not toggled the point.")
+	assert.Equal(keys, []rune{' '}, "Invalid keys.")
+	assert.Equal(resized, true, "The resize event is not sent.")
+}
@@ -3,6 +3,7 @@ package gui
 import (
 	"fmt"
 	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
 	"github.com/gopherjs/gopherjs/js"
 	"time"
 )
@@ -38,6 +39,15 @@ func showIcon(icon string) {
 	container.Set("className", "")
 }
 
+// Returns the topology of the parameter "topology" of the page URL, like "?topology=hexagonal".
+// The square topology is used when the parameter is missing or invalid.
+func getTopology() matrix.Topology {
+	search := js.Global.Get("location").Get("search")
+	params := js.Global.Get("URLSearchParams").New(search)
+	topology, _ := matrix.ParseTopology(params.Call("get", "topology").String())
+	return topology
+}
+
 func handlerError(err error) {
 	js.Global.Get("console").Call("error", err.Error())
 	js.Global.Call("alert", "Error")
@@ -97,7 +107,7 @@ func showModal(modalId string) {
 	modalc.Set("className", "show-modal")
 }
 
-func handleMenu(canvas *Canvas) {
+func handleMenu(canvas *Canvas, html *htmlCanvas) {
 	// set global variable will save the interval.
 	js.Global.Set(inVarName, nil)
 
@@ -112,7 +122,7 @@ func handleMenu(canvas *Canvas) {
 	}
 
 	// Event will show the animate elements when the mouse is moved.
-	canvas.GetJsCanvas().Call("addEventListener", "mousemove", func() { //  mousemove event.
+	html.GetJsCanvas().Call("addEventListener", "mousemove", func() { //  mousemove event.
 		go func() {
 			intId := js.Global.Get(inVarName)
 
@@ -153,37 +163,31 @@ func handleMenu(canvas *Canvas) {
 
 func Start() {
 	var canvas *Canvas
-	canvas, _ = NewCanvas(&[]game.Position{})
+	html := newHtmlCanvas()
+	topology := getTopology()
+	canvas, _ = NewCanvasWithTopology(html, &[]game.Position{}, topology)
 
 	// click event in canvas. Enable or disable matrix points.
-	canvas.GetJsCanvas().Call("addEventListener", "click", func(evt *js.Object) {
-		go func() {
-			x := evt.Get("clientX").Int()
-			y := evt.Get("clientY").Int()
-			canvas.ToggleMatrixPoint(x, y)
-		}()
+	html.OnClick(func(x, y int) {
+		canvas.ToggleMatrixPoint(x, y)
 	})
 
 	// Keypress event in window. Start or stop the game.
-	js.Global.Get("window").Call("addEventListener", "keypress", func(evt *js.Object) {
-		go func() {
-			if evt.Get("charCode").Int() == int(' ') {
-				togglePlayingGame(canvas)
-			}
-		}()
+	html.OnKey(func(key rune) {
+		if key == ' ' {
+			togglePlayingGame(canvas)
+		}
 	})
 
 	// Remake the canvas when the window size changes.
 	// It is rare!!! The matrix position no disappers when the matrix is removed.
 	// Maybe guilty are async functions.
-	js.Global.Get("window").Call("addEventListener", "resize", func(evt *js.Object) {
-		go func() {
-			canvas.Stop()
-			canvas, _ = NewCanvas(&[]game.Position{})
-		}()
+	html.OnResize(func() {
+		canvas.Stop()
+		canvas, _ = NewCanvasWithTopology(html, &[]game.Position{}, topology)
 	})
 
-	handleMenu(canvas)
+	handleMenu(canvas, html)
 
 	// Close modal function.
 	closeModalFun := func() {
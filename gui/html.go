@@ -0,0 +1,116 @@
+package gui
+
+import (
+	"math"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Canvas color cells whe these are enabled or disabled.
+var pointColors map[bool]string = map[bool]string{false: "#666666", true: "#ffeb3b"}
+
+// Renderer and input source of the HTML5 canvas.
+type htmlCanvas struct {
+	// Js canvas object.
+	canvas *js.Object
+}
+
+// Get the canvas js object from html dom.
+func getCanvas() *js.Object {
+	return js.Global.Get("document").Call("getElementById", "game-of-life")
+}
+
+// Makes the HTML5 canvas renderer. The canvas size is the window browser size.
+func newHtmlCanvas() *htmlCanvas {
+	canvas := getCanvas()
+
+	// Set the canvas size using the windows size.
+	ww := js.Global.Get("window").Get("innerWidth").Float()
+	wh := js.Global.Get("window").Get("innerHeight").Float()
+	canvas.Set("width", ww)
+	canvas.Set("height", wh)
+
+	return &htmlCanvas{canvas}
+}
+
+// Returns the js object where are saved the canvas.
+func (self *htmlCanvas) GetJsCanvas() *js.Object {
+	return self.canvas
+}
+
+func (self *htmlCanvas) GetSize() (int, int) {
+	w := self.canvas.Get("width").Float()
+	h := self.canvas.Get("height").Float()
+	return int(math.Ceil(w)), int(math.Ceil(h))
+}
+
+func (self *htmlCanvas) Clear() error {
+	w, h := self.GetSize()
+	self.canvas.Call("getContext", "2d").Call("clearRect", 0, 0, w, h)
+	return nil
+}
+
+func (self *htmlCanvas) DrawCell(x, y, size int, enabled bool) error {
+	ctx := self.canvas.Call("getContext", "2d")
+	ctx.Set("fillStyle", pointColors[enabled])
+	ctx.Call("fillRect", x+1, y+1, size-1, size-1)
+	return nil
+}
+
+func (self *htmlCanvas) DrawPolygon(vertices [][2]float64, enabled bool) error {
+	// The vertices are moved 1 pixel to the center, so the cells are separated as the squares.
+	var cx, cy float64
+	for _, v := range vertices {
+		cx += v[0] / float64(len(vertices))
+		cy += v[1] / float64(len(vertices))
+	}
+
+	ctx := self.canvas.Call("getContext", "2d")
+	ctx.Set("fillStyle", pointColors[enabled])
+	ctx.Call("beginPath")
+
+	for i, v := range vertices {
+		x, y := v[0], v[1]
+		if d := math.Hypot(x-cx, y-cy); d > 1 {
+			x, y = x-(x-cx)/d, y-(y-cy)/d
+		}
+
+		if i == 0 {
+			ctx.Call("moveTo", x, y)
+		} else {
+			ctx.Call("lineTo", x, y)
+		}
+	}
+
+	ctx.Call("closePath")
+	ctx.Call("fill")
+	return nil
+}
+
+// The HTML5 canvas shows the cells when they are drawn.
+func (self *htmlCanvas) Present() error {
+	return nil
+}
+
+func (self *htmlCanvas) OnClick(callback func(x, y int)) {
+	self.canvas.Call("addEventListener", "click", func(evt *js.Object) {
+		go callback(evt.Get("clientX").Int(), evt.Get("clientY").Int())
+	})
+}
+
+func (self *htmlCanvas) OnKey(callback func(key rune)) {
+	js.Global.Get("window").Call("addEventListener", "keypress", func(evt *js.Object) {
+		go callback(rune(evt.Get("charCode").Int()))
+	})
+}
+
+func (self *htmlCanvas) OnResize(callback func()) {
+	js.Global.Get("window").Call("addEventListener", "resize", func(evt *js.Object) {
+		go func() {
+			// Set the canvas size using the new windows size.
+			self.canvas.Set("width", js.Global.Get("window").Get("innerWidth").Float())
+			self.canvas.Set("height", js.Global.Get("window").Get("innerHeight").Float())
+			callback()
+		}()
+	})
+}
@@ -0,0 +1,125 @@
+package gui
+
+import (
+	"math"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Width of the hexagons, the distance between the centers of two hexagons of the same row.
+const hexWidth float64 = float64(ppp)
+
+// Distance between the center and the vertices of the hexagons.
+var hexRadius float64 = hexWidth / math.Sqrt(3)
+
+// Height of each row of hexagons. The rows overlap a quarter of the hexagon height.
+var hexRowHeight float64 = hexRadius * 3 / 2
+
+// Height of the triangles, the same as the squares.
+const triangleHeight float64 = float64(ppp)
+
+// Half of the base of the triangles, the width of each column of triangles.
+var triangleHalfBase float64 = triangleHeight / math.Sqrt(3)
+
+// Returns the size of a game, with the topology `topology`, that fills the surface of size
+// `width`x`height` pixels. The last points can be partially in the surface.
+func getTopologySize(topology matrix.Topology, width, height int) (int, int) {
+	w, h := float64(width), float64(height)
+
+	switch topology {
+	case matrix.TOPOLOGY_HEXAGONAL:
+		// Each row is shifted half hexagon, so the game is a rhombus inside the surface.
+		gh := int(math.Ceil(h / hexRowHeight))
+		gw := int(math.Ceil(w/hexWidth - float64(gh-1)/2))
+		return gw, gh
+	case matrix.TOPOLOGY_TRIANGULAR:
+		return int(math.Ceil(w / triangleHalfBase)), int(math.Ceil(h / triangleHeight))
+	}
+
+	return int(math.Ceil(w / float64(ppp))), int(math.Ceil(h / float64(ppp)))
+}
+
+// Returns the center of the hexagon of the position `x`, `y`.
+func getHexagonCenter(x, y int) (float64, float64) {
+	cx := hexWidth*(float64(x)+float64(y)/2) + hexWidth/2
+	cy := hexRowHeight*float64(y) + hexRadius
+	return cx, cy
+}
+
+// Returns the vertices of the cell of the position `x`, `y` in the topology `topology`,
+// clockwise and in pixels.
+func getCellPolygon(topology matrix.Topology, x, y int) [][2]float64 {
+	switch topology {
+	case matrix.TOPOLOGY_HEXAGONAL:
+		cx, cy := getHexagonCenter(x, y)
+		vertices := make([][2]float64, 6)
+		for i := range vertices {
+			// The hexagons point up: the first vertex is the top one.
+			angle := math.Pi/3*float64(i) - math.Pi/2
+			vertices[i] = [2]float64{cx + hexRadius*math.Cos(angle), cy + hexRadius*math.Sin(angle)}
+		}
+
+		return vertices
+	case matrix.TOPOLOGY_TRIANGULAR:
+		left, top := triangleHalfBase*float64(x), triangleHeight*float64(y)
+		right, bottom := left+2*triangleHalfBase, top+triangleHeight
+		middle := left + triangleHalfBase
+
+		if matrix.IsTriangleUp(x, y) {
+			return [][2]float64{{middle, top}, {right, bottom}, {left, bottom}}
+		}
+
+		return [][2]float64{{left, top}, {right, top}, {middle, bottom}}
+	}
+
+	left, top := float64(x*ppp), float64(y*ppp)
+	size := float64(ppp)
+	return [][2]float64{{left, top}, {left + size, top}, {left + size, top + size}, {left, top + size}}
+}
+
+// Returns the position of the hexagon nearest to the axial coordinates `q`, `r`, that can
+// be fractional.
+func roundHexagon(q, r float64) (int, int) {
+	// The third cube coordinate is -q-r. The coordinate with the biggest rounding error is
+	// recalculated using the other two.
+	s := -q - r
+	rq, rr, rs := math.Round(q), math.Round(r), math.Round(s)
+	dq, dr, ds := math.Abs(rq-q), math.Abs(rr-r), math.Abs(rs-s)
+
+	if dq > dr && dq > ds {
+		rq = -rr - rs
+	} else if dr > ds {
+		rr = -rq - rs
+	}
+
+	return int(rq), int(rr)
+}
+
+// Returns the position of the cell, in the topology `topology`, that has the pixel `x`, `y`.
+func getCellPosition(topology matrix.Topology, x, y int) (int, int) {
+	px, py := float64(x), float64(y)
+
+	switch topology {
+	case matrix.TOPOLOGY_HEXAGONAL:
+		// Relative to the center of the hexagon 0x0.
+		px, py = px-hexWidth/2, py-hexRadius
+		r := py / hexRowHeight
+		q := px/hexWidth - r/2
+		return roundHexagon(q, r)
+	case matrix.TOPOLOGY_TRIANGULAR:
+		row := int(math.Floor(py / triangleHeight))
+		column := int(math.Floor(px / triangleHalfBase))
+
+		// The column of half base has the left half of the triangle `column` and the right
+		// half of the previous triangle, split by a diagonal.
+		fx := px/triangleHalfBase - float64(column)
+		fy := py/triangleHeight - float64(row)
+		if matrix.IsTriangleUp(column, row) && fy < 1-fx || !matrix.IsTriangleUp(column, row) && fy > fx {
+			column--
+		}
+
+		return column, row
+	}
+
+	return x / ppp, y / ppp
+}
@@ -0,0 +1,75 @@
+package gui
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the center of the polygon `vertices`, rounded to pixels.
+func auxPolygonCenter(vertices [][2]float64) (int, int) {
+	var cx, cy float64
+	for _, v := range vertices {
+		cx += v[0] / float64(len(vertices))
+		cy += v[1] / float64(len(vertices))
+	}
+
+	return int(math.Round(cx)), int(math.Round(cy))
+}
+
+// Test the clicks in the center of each cell are mapped to the cell.
+func TestGetCellPosition(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, topology := range []matrix.Topology{matrix.TOPOLOGY_SQUARE, matrix.TOPOLOGY_HEXAGONAL, matrix.TOPOLOGY_TRIANGULAR} {
+		for x := 0; x < 12; x++ {
+			for y := 0; y < 12; y++ {
+				cx, cy := auxPolygonCenter(getCellPolygon(topology, x, y))
+				mx, my := getCellPosition(topology, cx, cy)
+				message := fmt.Sprintf("Invalid position of the cell %dx%d in the topology %s.", x, y, topology)
+				assert.Equal([2]int{mx, my}, [2]int{x, y}, message)
+			}
+		}
+	}
+}
+
+// Test the clicks near the edges of the cells.
+func TestGetCellPositionEdges(t *testing.T) {
+	assert := assert.New(t)
+	hexagonal, triangular := matrix.TOPOLOGY_HEXAGONAL, matrix.TOPOLOGY_TRIANGULAR
+
+	// The triangle 0x0 points up and the triangle 1x0 points down: the left corners of the
+	// first row are outside of the triangle 0x0.
+	x, y := getCellPosition(triangular, 1, 1)
+	assert.Equal([2]int{x, y}, [2]int{-1, 0}, "Invalid triangle of the top-left corner.")
+	x, y = getCellPosition(triangular, 1, ppp-1)
+	assert.Equal([2]int{x, y}, [2]int{0, 0}, "Invalid triangle of the bottom-left corner.")
+	x, y = getCellPosition(triangular, int(2*triangleHalfBase)-1, 1)
+	assert.Equal([2]int{x, y}, [2]int{1, 0}, "Invalid triangle of the top-right corner.")
+
+	// The second row of hexagons is shifted half hexagon to the right.
+	x, y = getCellPosition(hexagonal, 1, int(hexRadius))
+	assert.Equal([2]int{x, y}, [2]int{0, 0}, "Invalid hexagon of the left edge.")
+	x, y = getCellPosition(hexagonal, 1, int(hexRowHeight+hexRadius))
+	assert.Equal([2]int{x, y}, [2]int{-1, 1}, "Invalid hexagon of the second row.")
+	x, y = getCellPosition(hexagonal, ppp+1, int(hexRowHeight+hexRadius))
+	assert.Equal([2]int{x, y}, [2]int{0, 1}, "Invalid hexagon of the second row.")
+}
+
+// Test the size of the games that fill a surface.
+func TestGetTopologySize(t *testing.T) {
+	assert := assert.New(t)
+
+	w, h := getTopologySize(matrix.TOPOLOGY_SQUARE, 10*ppp-5, 12*ppp)
+	assert.Equal([2]int{w, h}, [2]int{10, 12}, "Invalid size of the square topology.")
+
+	// 30 rows of hexagons, the last one shifted 14.5 hexagons.
+	w, h = getTopologySize(matrix.TOPOLOGY_HEXAGONAL, 40*ppp, int(30*hexRowHeight))
+	assert.Equal([2]int{w, h}, [2]int{26, 30}, "Invalid size of the hexagonal topology.")
+
+	w, h = getTopologySize(matrix.TOPOLOGY_TRIANGULAR, int(20*triangleHalfBase), 12*ppp)
+	assert.Equal([2]int{w, h}, [2]int{20, 12}, "Invalid size of the triangular topology.")
+}
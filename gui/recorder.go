@@ -0,0 +1,134 @@
+package gui
+
+import (
+	"math"
+)
+
+// Renderer that saves the cells drawn in memory. Used to test the canvas without browser.
+type RecordingRenderer struct {
+	width, height int
+
+	// Cells drawn since the last clear, by position. True when the cell is enabled.
+	cells map[[2]int]bool
+
+	// Cells shown in the last frame.
+	frame map[[2]int]bool
+
+	// Number of frames shown and clears.
+	framesNum int
+	clearsNum int
+}
+
+// Makes a new recording renderer of size `width`x`height` pixels.
+func NewRecordingRenderer(width, height int) *RecordingRenderer {
+	return &RecordingRenderer{width, height, map[[2]int]bool{}, map[[2]int]bool{}, 0, 0}
+}
+
+func (self *RecordingRenderer) GetSize() (int, int) {
+	return self.width, self.height
+}
+
+func (self *RecordingRenderer) Clear() error {
+	self.cells = map[[2]int]bool{}
+	self.clearsNum++
+	return nil
+}
+
+func (self *RecordingRenderer) DrawCell(x, y, size int, enabled bool) error {
+	self.cells[[2]int{x, y}] = enabled
+	return nil
+}
+
+// The polygons are saved by the top-left corner of their bounding box, rounded down.
+func (self *RecordingRenderer) DrawPolygon(vertices [][2]float64, enabled bool) error {
+	minX, minY := math.Inf(1), math.Inf(1)
+	for _, v := range vertices {
+		minX, minY = math.Min(minX, v[0]), math.Min(minY, v[1])
+	}
+
+	self.cells[[2]int{int(math.Floor(minX)), int(math.Floor(minY))}] = enabled
+	return nil
+}
+
+func (self *RecordingRenderer) Present() error {
+	self.frame = self.cells
+	self.cells = map[[2]int]bool{}
+	for position, enabled := range self.frame {
+		self.cells[position] = enabled
+	}
+
+	self.framesNum++
+	return nil
+}
+
+// Checks if the cell whose top-left corner is `x`, `y` is shown enabled in the last frame.
+func (self *RecordingRenderer) IsCellEnabled(x, y int) bool {
+	return self.frame[[2]int{x, y}]
+}
+
+// Returns the number of cells shown in the last frame.
+func (self *RecordingRenderer) GetCellsNum() int {
+	return len(self.frame)
+}
+
+// Returns the number of cells shown enabled in the last frame.
+func (self *RecordingRenderer) GetCellsEnabledNum() int {
+	n := 0
+	for _, enabled := range self.frame {
+		if enabled {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Returns the number of frames shown.
+func (self *RecordingRenderer) GetFramesNum() int {
+	return self.framesNum
+}
+
+// Returns the number of times the renderer was cleared.
+func (self *RecordingRenderer) GetClearsNum() int {
+	return self.clearsNum
+}
+
+// Input source whose events are sent calling its functions. Used to test without browser.
+type ManualInput struct {
+	clicks  []func(x, y int)
+	keys    []func(key rune)
+	resizes []func()
+}
+
+func (self *ManualInput) OnClick(callback func(x, y int)) {
+	self.clicks = append(self.clicks, callback)
+}
+
+func (self *ManualInput) OnKey(callback func(key rune)) {
+	self.keys = append(self.keys, callback)
+}
+
+func (self *ManualInput) OnResize(callback func()) {
+	self.resizes = append(self.resizes, callback)
+}
+
+// Sends a click in the position `x`, `y`.
+func (self *ManualInput) Click(x, y int) {
+	for _, callback := range self.clicks {
+		callback(x, y)
+	}
+}
+
+// Sends the key `key`.
+func (self *ManualInput) Key(key rune) {
+	for _, callback := range self.keys {
+		callback(key)
+	}
+}
+
+// Sends a resize event.
+func (self *ManualInput) Resize() {
+	for _, callback := range self.resizes {
+		callback()
+	}
+}
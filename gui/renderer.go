@@ -0,0 +1,33 @@
+package gui
+
+// Surface where the game is drawn. The positions and the sizes are in pixels.
+type Renderer interface {
+	// Returns the width and the height of the surface.
+	GetSize() (int, int)
+
+	// Removes everything drawn.
+	Clear() error
+
+	// Draws the cell of size `size`x`size` whose top-left corner is in `x`, `y`.
+	// `enabled` indicates if the point of the cell is enabled.
+	DrawCell(x, y, size int, enabled bool) error
+
+	// Draws the cell whose vertices are `vertices`, used by the hexagonal and the triangular
+	// topologies. `enabled` indicates if the point of the cell is enabled.
+	DrawPolygon(vertices [][2]float64, enabled bool) error
+
+	// Shows the cells drawn since the last call.
+	Present() error
+}
+
+// Source of the user events. The positions are in pixels.
+type InputSource interface {
+	// Calls `callback` with the position of each click.
+	OnClick(callback func(x, y int))
+
+	// Calls `callback` with the character of each key pressed.
+	OnKey(callback func(key rune))
+
+	// Calls `callback` when the size of the surface changes.
+	OnResize(callback func())
+}
@@ -0,0 +1,467 @@
+package hashlife
+
+import (
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Maximum number of nodes, by default, before collecting the garbage.
+const DEFAULT_MAX_NODES int = 1 << 22
+
+// Minimum level of the root node.
+const minLevel uint = 3
+
+// Infinite universe that runs the game cycles using the HashLife algorithm.
+// The points are stored in a quadtree of canonical nodes and the cycles of each node are
+// memoised, so the patterns with repetitive structure can advance billions of cycles.
+// The root node is centered in the position 0, 0.
+type Universe struct {
+	// Table with the canonical nodes.
+	cache *nodeCache
+
+	// Node with all the points.
+	root *Node
+
+	// Rule used in the cycles.
+	rule *game.Rule
+
+	// Number of cycles run.
+	generation uint64
+
+	// Maximum number of nodes before collecting the garbage. Zero disables it.
+	maxNodes int
+}
+
+// Makes a new empty universe that runs the cycles using the rule `rule`.
+// The Conway's rule is used when `rule` is nil.
+// It returns an error whether the rule has B0, because the empty space would change.
+func New(rule *game.Rule) (*Universe, error) {
+	if rule == nil {
+		rule = game.MustParseRule(game.CONWAY_RULE)
+	}
+
+	if rule.IsBirth(0) {
+		return nil, game.InvalidRuleError(rule.String(), "the B0 rules need a bounded storage")
+	}
+
+	if rule.GetStates() > 2 {
+		return nil, game.InvalidRuleError(rule.String(), "the Generations rules need the dense storage")
+	}
+
+	if rule.IsLargerThanLife() {
+		return nil, game.InvalidRuleError(rule.String(), "the Larger than Life rules need the dense storage")
+	}
+
+	cache := newNodeCache()
+	u := &Universe{cache, cache.getEmpty(minLevel), rule, 0, DEFAULT_MAX_NODES}
+	return u, nil
+}
+
+// Makes a new universe with the points enabled of the universe `m`.
+// The edges of the bounded universes are ignored: the points are in an infinite universe.
+func FromUniverse(m matrix.Universe, rule *game.Rule) (*Universe, error) {
+	u, err := New(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	m.ForEachEnabled(func(x, y int) {
+		u.EnablePoint(x, y)
+	})
+
+	return u, nil
+}
+
+// Makes a new universe with the points enabled and the rule of the game `g`.
+// The generation of the universe is the number of cycles of the game.
+// It returns an error whether the game does not use the Moore neighbourhood or the square
+// topology.
+func FromGame(g *game.Game) (*Universe, error) {
+	if !g.GetNeighbourhood().IsMoore() {
+		return nil, game.InvalidNeighbourhoodError("HashLife only uses the Moore neighbourhood")
+	}
+
+	if topology := g.GetTopology(); topology != matrix.TOPOLOGY_SQUARE {
+		return nil, matrix.TopologyError(topology, "HashLife only uses the square cells")
+	}
+
+	u, err := FromUniverse(g.GetMatrix(), g.GetRule())
+	if err != nil {
+		return nil, err
+	}
+
+	u.generation = uint64(g.GetCyclesNum())
+	return u, nil
+}
+
+// Returns half of the size of the root node.
+func (self *Universe) getHalf() int {
+	return 1 << (self.root.level - 1)
+}
+
+// Returns the node `n` with the point `x`, `y`, relative to the node corner, changed.
+func (self *Universe) setPoint(n *Node, x, y int, enabled bool) *Node {
+	if n.level == 0 {
+		if enabled {
+			return onLeaf
+		}
+
+		return offLeaf
+	}
+
+	half := 1 << (n.level - 1)
+	nw, ne, sw, se := n.nw, n.ne, n.sw, n.se
+
+	switch {
+	case x < half && y < half:
+		nw = self.setPoint(nw, x, y, enabled)
+	case y < half:
+		ne = self.setPoint(ne, x-half, y, enabled)
+	case x < half:
+		sw = self.setPoint(sw, x, y-half, enabled)
+	default:
+		se = self.setPoint(se, x-half, y-half, enabled)
+	}
+
+	return self.cache.join(nw, ne, sw, se)
+}
+
+// Changes the point of the position `x`, `y`, expanding the root node when it is needed.
+func (self *Universe) changePoint(x, y int, enabled bool) {
+	for half := self.getHalf(); x < -half || y < -half || x >= half || y >= half; half = self.getHalf() {
+		self.root = self.cache.expand(self.root)
+	}
+
+	half := self.getHalf()
+	self.root = self.setPoint(self.root, x+half, y+half, enabled)
+}
+
+// Enable the point of the position `x`, `y`.
+func (self *Universe) EnablePoint(x, y int) {
+	self.changePoint(x, y, true)
+}
+
+// Disable the point of the position `x`, `y`.
+func (self *Universe) DisablePoint(x, y int) {
+	self.changePoint(x, y, false)
+}
+
+// Checks if the point of the position `x`, `y` is enabled.
+func (self *Universe) IsEnabled(x, y int) bool {
+	half := self.getHalf()
+	if x < -half || y < -half || x >= half || y >= half {
+		return false
+	}
+
+	n, x, y := self.root, x+half, y+half
+	for n.level > 0 {
+		half = 1 << (n.level - 1)
+
+		switch {
+		case x < half && y < half:
+			n = n.nw
+		case y < half:
+			n, x = n.ne, x-half
+		case x < half:
+			n, y = n.sw, y-half
+		default:
+			n, x, y = n.se, x-half, y-half
+		}
+	}
+
+	return n.IsEnabled()
+}
+
+// Returns the point `x`, `y` of the node `n` of level 2.
+func getPoint4x4(n *Node, x, y int) bool {
+	q := [2][2]*Node{{n.nw, n.sw}, {n.ne, n.se}}[x/2][y/2]
+	return [2][2]*Node{{q.nw, q.sw}, {q.ne, q.se}}[x%2][y%2].IsEnabled()
+}
+
+// Returns the center of the node `n` of level 2 in the next cycle.
+func (self *Universe) step4x4(n *Node) *Node {
+	var next [4]*Node
+
+	for i, p := range [4][2]int{{1, 1}, {2, 1}, {1, 2}, {2, 2}} {
+		mask := game.GetAdjacentsMask(func(dx, dy int) bool {
+			return getPoint4x4(n, p[0]+dx, p[1]+dy)
+		})
+
+		next[i] = offLeaf
+		if self.rule.NextStateMask(getPoint4x4(n, p[0], p[1]), mask) {
+			next[i] = onLeaf
+		}
+	}
+
+	return self.cache.join(next[0], next[1], next[2], next[3])
+}
+
+// Returns the center of the node `n`, of level 2 or higher, after 2^j cycles.
+// The nodes can only advance 2^(level - 2) cycles, so `j` is reduced to it.
+func (self *Universe) successor(n *Node, j uint) *Node {
+	if n.population == 0 {
+		return self.cache.getEmpty(n.level - 1)
+	}
+
+	if j > n.level-2 {
+		j = n.level - 2
+	}
+
+	if n.next != nil && n.nextStep == j {
+		return n.next
+	}
+
+	var s *Node
+	c := self.cache
+
+	if n.level == 2 {
+		s = self.step4x4(n)
+	} else {
+		// Nine nodes of level - 1 that overlap, advanced 2^j cycles.
+		c1 := self.successor(n.nw, j)
+		c2 := self.successor(c.join(n.nw.ne, n.ne.nw, n.nw.se, n.ne.sw), j)
+		c3 := self.successor(n.ne, j)
+		c4 := self.successor(c.join(n.nw.sw, n.nw.se, n.sw.nw, n.sw.ne), j)
+		c5 := self.successor(c.center(n), j)
+		c6 := self.successor(c.join(n.ne.sw, n.ne.se, n.se.nw, n.se.ne), j)
+		c7 := self.successor(n.sw, j)
+		c8 := self.successor(c.join(n.sw.ne, n.se.nw, n.sw.se, n.se.sw), j)
+		c9 := self.successor(n.se, j)
+
+		if j < n.level-2 {
+			// The cycles are already run. Take the centers.
+			s = c.join(
+				c.join(c1.se, c2.sw, c4.ne, c5.nw),
+				c.join(c2.se, c3.sw, c5.ne, c6.nw),
+				c.join(c4.se, c5.sw, c7.ne, c8.nw),
+				c.join(c5.se, c6.sw, c8.ne, c9.nw))
+		} else {
+			// Run the second half of the cycles.
+			s = c.join(
+				self.successor(c.join(c1, c2, c4, c5), j),
+				self.successor(c.join(c2, c3, c5, c6), j),
+				self.successor(c.join(c4, c5, c7, c8), j),
+				self.successor(c.join(c5, c6, c8, c9), j))
+		}
+	}
+
+	n.next, n.nextStep = s, j
+	return s
+}
+
+// Checks if all the points of the root node are in its inner quarter,
+// so they can not escape of the center of the node in 2^(level - 3) cycles.
+func (self *Universe) isPadded() bool {
+	r := self.root
+	return r.nw.population == r.nw.se.se.population &&
+		r.ne.population == r.ne.sw.sw.population &&
+		r.sw.population == r.sw.ne.ne.population &&
+		r.se.population == r.se.nw.nw.population
+}
+
+// Runs 2^j cycles.
+func (self *Universe) StepPow2(j uint) {
+	for self.root.level < j+minLevel || !self.isPadded() {
+		self.root = self.cache.expand(self.root)
+	}
+
+	self.root = self.successor(self.root, j)
+	self.generation += uint64(1) << j
+
+	if self.maxNodes > 0 && self.cache.size() > self.maxNodes {
+		self.CollectGarbage()
+	}
+}
+
+// Runs `n` cycles.
+func (self *Universe) Advance(n uint64) {
+	for j := uint(0); n > 0; j++ {
+		if n&1 == 1 {
+			self.StepPow2(j)
+		}
+
+		n >>= 1
+	}
+}
+
+// Removes the nodes that are not used by the universe and the memoised cycles.
+func (self *Universe) CollectGarbage() {
+	self.cache.collect(self.root)
+}
+
+// Sets the maximum number of nodes before collecting the garbage. Zero disables it.
+func (self *Universe) SetMaxNodes(n int) {
+	self.maxNodes = n
+}
+
+// Returns the number of nodes stored.
+func (self *Universe) GetNodesNum() int {
+	return self.cache.size()
+}
+
+// Returns the root node.
+func (self *Universe) GetRoot() *Node {
+	return self.root
+}
+
+// Returns the node with the quadrants `nw`, `ne`, `sw` and `se`, that must have the same level.
+// Used to build the quadtree of the universe. The nodes must be made by the universe.
+func (self *Universe) JoinNodes(nw, ne, sw, se *Node) *Node {
+	return self.cache.join(nw, ne, sw, se)
+}
+
+// Returns the node of level `level` without points enabled.
+func (self *Universe) GetEmptyNode(level uint) *Node {
+	return self.cache.getEmpty(level)
+}
+
+// Sets the root node, that must be made by the universe. The root node is centered in the
+// position 0, 0 and it is expanded when its level is too small.
+func (self *Universe) SetRoot(root *Node) {
+	if root.level == 0 {
+		root = self.cache.join(offLeaf, offLeaf, offLeaf, root)
+	}
+
+	for root.level < minLevel {
+		root = self.cache.expand(root)
+	}
+
+	self.root = root
+}
+
+// Returns the rule used in the cycles.
+func (self *Universe) GetRule() *game.Rule {
+	return self.rule
+}
+
+// Returns the number of cycles run.
+func (self *Universe) GetGeneration() uint64 {
+	return self.generation
+}
+
+// Sets the number of cycles run.
+func (self *Universe) SetGeneration(generation uint64) {
+	self.generation = generation
+}
+
+// Get the points enabled.
+func (self *Universe) GetPointsEnabled() int {
+	return self.root.population
+}
+
+// Calls `callback` with the position of each point enabled of the node `n`, whose corner is
+// in the position `x`, `y`, that is inside of the rectangle `rect`.
+func forEachEnabled(n *Node, x, y int, rect matrix.Rect, callback func(x, y int)) {
+	if n.population == 0 {
+		return
+	}
+
+	size := 1 << n.level
+	if x > rect.MaxX || y > rect.MaxY || x+size-1 < rect.MinX || y+size-1 < rect.MinY {
+		return
+	}
+
+	if n.level == 0 {
+		callback(x, y)
+		return
+	}
+
+	half := size / 2
+	forEachEnabled(n.nw, x, y, rect, callback)
+	forEachEnabled(n.ne, x+half, y, rect, callback)
+	forEachEnabled(n.sw, x, y+half, rect, callback)
+	forEachEnabled(n.se, x+half, y+half, rect, callback)
+}
+
+// Calls the function `callback` with the position of each point enabled inside of the
+// rectangle `rect`.
+func (self *Universe) ForEachEnabledIn(rect matrix.Rect, callback func(x, y int)) {
+	half := self.getHalf()
+	forEachEnabled(self.root, -half, -half, rect, callback)
+}
+
+// Calls the function `callback` with the position of each point enabled.
+func (self *Universe) ForEachEnabled(callback func(x, y int)) {
+	half := self.getHalf()
+	self.ForEachEnabledIn(matrix.Rect{MinX: -half, MinY: -half, MaxX: half - 1, MaxY: half - 1}, callback)
+}
+
+// Returns the bounding box of the node `n`, relative to its corner.
+// `memo` saves the bounding boxes of the nodes already visited.
+func getNodeBoundingBox(n *Node, memo map[*Node]matrix.Rect) matrix.Rect {
+	if n.level == 0 {
+		return matrix.Rect{}
+	}
+
+	if box, ok := memo[n]; ok {
+		return box
+	}
+
+	found := false
+	box := matrix.Rect{}
+	half := 1 << (n.level - 1)
+	quadrants := [4]*Node{n.nw, n.ne, n.sw, n.se}
+
+	for i, q := range quadrants {
+		if q.population == 0 {
+			continue
+		}
+
+		qbox := getNodeBoundingBox(q, memo)
+		dx, dy := (i%2)*half, (i/2)*half
+		qbox = matrix.Rect{
+			MinX: qbox.MinX + dx,
+			MinY: qbox.MinY + dy,
+			MaxX: qbox.MaxX + dx,
+			MaxY: qbox.MaxY + dy,
+		}
+
+		if !found {
+			box, found = qbox, true
+		}
+
+		box = box.Extend(qbox.MinX, qbox.MinY).Extend(qbox.MaxX, qbox.MaxY)
+	}
+
+	memo[n] = box
+	return box
+}
+
+// Returns the smallest rectangle with all the points enabled.
+// The second value is false when there are not points enabled.
+func (self *Universe) GetBoundingBox() (matrix.Rect, bool) {
+	if self.root.population == 0 {
+		return matrix.Rect{}, false
+	}
+
+	half := self.getHalf()
+	box := getNodeBoundingBox(self.root, map[*Node]matrix.Rect{})
+	return matrix.Rect{
+		MinX: box.MinX - half,
+		MinY: box.MinY - half,
+		MaxX: box.MaxX - half,
+		MaxY: box.MaxY - half,
+	}, true
+}
+
+// Copies the points enabled to the universe `m`. The position `x`, `y` is copied to the
+// position 0, 0 of `m`. With bounded universes only the points inside of `m` are copied.
+// Returns the error of the universe `m`.
+func (self *Universe) ExportTo(m matrix.Universe, x, y int) error {
+	var err error
+	half := self.getHalf()
+	rect := matrix.Rect{MinX: -half, MinY: -half, MaxX: half - 1, MaxY: half - 1}
+
+	if m.IsBounded() {
+		w, h := m.GetSize()
+		rect = matrix.Rect{MinX: x, MinY: y, MaxX: x + w - 1, MaxY: y + h - 1}
+	}
+
+	self.ForEachEnabledIn(rect, func(px, py int) {
+		if e := m.EnablePoint(px-x, py-y); e != nil && err == nil {
+			err = e
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,215 @@
+package hashlife
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+var glider []game.Position = []game.Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+
+// Returns the positions enabled in the universe `u`.
+func auxPositions(u *Universe) map[game.Position]bool {
+	positions := map[game.Position]bool{}
+	u.ForEachEnabled(func(x, y int) {
+		positions[game.Position{x, y}] = true
+	})
+
+	return positions
+}
+
+// Returns the positions enabled in the game `g`.
+func auxGamePositions(g *game.Game) map[game.Position]bool {
+	positions := map[game.Position]bool{}
+	g.GetMatrix().ForEachEnabled(func(x, y int) {
+		positions[game.Position{x, y}] = true
+	})
+
+	return positions
+}
+
+// Test the functions EnablePoint, DisablePoint and IsEnabled.
+func TestEnablePoint(t *testing.T) {
+	assert := assert.New(t)
+	u, _ := New(nil)
+
+	for _, p := range [][2]int{{0, 0}, {-1, -1}, {100, -3}, {-5000, 7000}} {
+		u.EnablePoint(p[0], p[1])
+		assert.Equal(u.IsEnabled(p[0], p[1]), true, fmt.Sprintf("The point %dx%d is disabled.", p[0], p[1]))
+	}
+
+	assert.Equal(u.GetPointsEnabled(), 4, "Invalid points enabled.")
+	assert.Equal(u.IsEnabled(1, 1), false, "The point 1x1 is enabled.")
+	assert.Equal(u.IsEnabled(1<<40, 0), false, "The point 2^40x0 is enabled.")
+
+	u.DisablePoint(100, -3)
+	assert.Equal(u.IsEnabled(100, -3), false, "The point 100x-3 is enabled.")
+	assert.Equal(u.GetPointsEnabled(), 3, "Invalid points enabled.")
+
+	box, ok := u.GetBoundingBox()
+	assert.Equal(ok, true, "There is not bounding box.")
+	assert.Equal(box, matrix.Rect{MinX: -5000, MinY: -1, MaxX: 0, MaxY: 7000}, "Invalid bounding box.")
+}
+
+// Test the rules with B0 are not allowed.
+func TestNewB0Error(t *testing.T) {
+	assert := assert.New(t)
+	_, err := New(game.MustParseRule("B0/S8"))
+	assert.Equal(err, game.InvalidRuleError("B0/S8", "the B0 rules need a bounded storage"), "The error does not match.")
+}
+
+// Test the games with other neighbourhoods are not allowed.
+func TestFromGameNeighbourhoodError(t *testing.T) {
+	assert := assert.New(t)
+	n, _ := game.ParseNeighbourhood("vonneumann")
+	options := game.Options{Storage: matrix.STORAGE_SPARSE, Neighbourhood: n}
+	g, _ := game.NewWithOptions(0, 0, []game.Position{{0, 0}}, options)
+
+	_, err := FromGame(g)
+	message := "HashLife only uses the Moore neighbourhood"
+	assert.Equal(err, game.InvalidNeighbourhoodError(message), "The error does not match.")
+}
+
+// Test the games with other topologies are not allowed.
+func TestFromGameTopologyError(t *testing.T) {
+	assert := assert.New(t)
+	options := game.Options{Storage: matrix.STORAGE_DENSE, Topology: matrix.TOPOLOGY_HEXAGONAL}
+	g, _ := game.NewWithOptions(10, 10, nil, options)
+
+	_, err := FromGame(g)
+	expected := matrix.TopologyError(matrix.TOPOLOGY_HEXAGONAL, "HashLife only uses the square cells")
+	assert.Equal(err, expected, "The error does not match.")
+}
+
+// Test the HashLife cycles are the same than the cycles of the game.
+func TestAdvanceSameAsGame(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+
+	for _, rule := range []string{game.CONWAY_RULE, "B36/S23", "B3678/S34678", "B2/S", "B3/S2-i34q", "B2-a/S12"} {
+		positions := make([]game.Position, 120)
+		for i := range positions {
+			positions[i] = game.Position{r.Intn(16) - 8, r.Intn(16) - 8}
+		}
+
+		options := game.Options{Rule: game.MustParseRule(rule), Storage: matrix.STORAGE_SPARSE}
+		g, _ := game.NewWithOptions(0, 0, positions, options)
+		u, err := FromGame(g)
+		assert.Equal(err, nil, "There is an error.")
+
+		for _, n := range []uint64{1, 1, 2, 3, 5, 8, 13, 21} {
+			for i := uint64(0); i < n; i++ {
+				g.Cycle()
+			}
+
+			u.Advance(n)
+			message := fmt.Sprintf("Invalid generation %d (%s).", g.GetCyclesNum(), rule)
+			assert.Equal(u.GetGeneration(), uint64(g.GetCyclesNum()), message)
+			assert.Equal(u.GetPointsEnabled(), g.GetMatrix().GetPointsEnabled(), message)
+			assert.Equal(auxPositions(u), auxGamePositions(g), message)
+		}
+	}
+}
+
+// Test the glider after 2^40 cycles.
+func TestStepPow2Glider(t *testing.T) {
+	assert := assert.New(t)
+	u, _ := New(nil)
+
+	for _, p := range glider {
+		u.EnablePoint(p[0], p[1])
+	}
+
+	u.StepPow2(40)
+	assert.Equal(u.GetGeneration(), uint64(1)<<40, "Invalid generation.")
+	assert.Equal(u.GetPointsEnabled(), len(glider), "Invalid points enabled.")
+
+	// The glider moves 1 point in diagonal each 4 cycles.
+	d := 1 << 38
+	for _, p := range glider {
+		enabled := u.IsEnabled(p[0]+d, p[1]+d)
+		assert.Equal(enabled, true, fmt.Sprintf("The point %dx%d is disabled.", p[0]+d, p[1]+d))
+	}
+
+	box, _ := u.GetBoundingBox()
+	assert.Equal(box, matrix.Rect{MinX: d, MinY: d, MaxX: d + 2, MaxY: d + 2}, "Invalid bounding box.")
+}
+
+// Test the garbage collection removes nodes and keeps the points.
+func TestCollectGarbage(t *testing.T) {
+	assert := assert.New(t)
+	// R-pentomino.
+	positions := []game.Position{{1, 0}, {2, 0}, {0, 1}, {1, 1}, {1, 2}}
+	g, _ := game.NewWithOptions(0, 0, positions, game.Options{Storage: matrix.STORAGE_SPARSE})
+	u, _ := FromGame(g)
+	u.SetMaxNodes(0)
+
+	u.Advance(100)
+	before := u.GetNodesNum()
+	u.CollectGarbage()
+	assert.Equal(u.GetNodesNum() < before, true, "The garbage is not collected.")
+
+	// The garbage is collected automatically.
+	u.SetMaxNodes(500)
+	u.Advance(100)
+	assert.Equal(u.GetNodesNum() <= 500, true, "The garbage is not collected.")
+
+	for i := 0; i < 200; i++ {
+		g.Cycle()
+	}
+
+	assert.Equal(auxPositions(u), auxGamePositions(g), "Invalid positions after collecting the garbage.")
+}
+
+// Test the function ExportTo with dense and sparse universes.
+func TestExportTo(t *testing.T) {
+	assert := assert.New(t)
+	u, _ := New(nil)
+	for _, p := range glider {
+		u.EnablePoint(p[0]-10, p[1]-10)
+	}
+
+	m, _ := matrix.New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE)
+	err := u.ExportTo(m, -11, -11)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(m.GetPointsEnabled(), len(glider), "Invalid points enabled.")
+
+	for _, p := range glider {
+		enabled, _ := m.IsEnabled(p[0]+1, p[1]+1)
+		assert.Equal(enabled, true, fmt.Sprintf("The point %dx%d is disabled.", p[0]+1, p[1]+1))
+	}
+
+	// Only the window is copied.
+	m.Reset()
+	u.ExportTo(m, -9, -9)
+	assert.Equal(m.GetPointsEnabled(), 3, "Invalid points enabled.")
+
+	s := matrix.NewSparse()
+	u.ExportTo(s, 0, 0)
+	enabled, _ := s.IsEnabled(-9, -10)
+	assert.Equal(s.GetPointsEnabled(), len(glider), "Invalid points enabled.")
+	assert.Equal(enabled, true, "The point -9x-10 is disabled.")
+}
+
+// Test the functions JoinNodes, GetEmptyNode and SetRoot.
+func TestSetRoot(t *testing.T) {
+	assert := assert.New(t)
+	u, _ := New(nil)
+
+	// Node of level 1 with the point of the south-east enabled.
+	n := u.JoinNodes(Leaf(false), Leaf(false), Leaf(false), Leaf(true))
+	assert.Equal(n, u.JoinNodes(Leaf(false), Leaf(false), Leaf(false), Leaf(true)), "The nodes are not canonical.")
+	assert.Equal(u.GetEmptyNode(1), u.JoinNodes(Leaf(false), Leaf(false), Leaf(false), Leaf(false)), "Invalid empty node.")
+
+	// The small root nodes are expanded around the center.
+	u.SetRoot(n)
+	assert.Equal(u.GetRoot().GetLevel(), minLevel, "Invalid level.")
+	assert.Equal(auxPositions(u), map[game.Position]bool{{0, 0}: true}, "Invalid positions.")
+
+	u.SetRoot(Leaf(true))
+	assert.Equal(auxPositions(u), map[game.Position]bool{{0, 0}: true}, "Invalid positions of the leaf.")
+}
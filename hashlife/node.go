@@ -0,0 +1,147 @@
+package hashlife
+
+// Node of the quadtree. A node of level `k` is a square of 2^k x 2^k points split in four
+// nodes of level `k - 1`. The nodes of level 0 are single points.
+// The nodes are canonical: two nodes with the same points are the same node, so they
+// are immutable and they can be compared using the pointers.
+type Node struct {
+	// Level of the node: its size is 2^level.
+	level uint
+
+	// Quadrants of the node: north-west, north-east, south-west and south-east.
+	nw, ne, sw, se *Node
+
+	// Number of points enabled.
+	population int
+
+	// Memoised center of the node after 2^nextStep cycles.
+	next     *Node
+	nextStep uint
+}
+
+// Key used to canonicalise the nodes.
+type nodeKey [4]*Node
+
+// The two nodes of level 0.
+var (
+	offLeaf = &Node{}
+	onLeaf  = &Node{population: 1}
+)
+
+// Returns the node of level 0 enabled, or disabled, according to `enabled`.
+func Leaf(enabled bool) *Node {
+	if enabled {
+		return onLeaf
+	}
+
+	return offLeaf
+}
+
+// Returns the level of the node.
+func (self *Node) GetLevel() uint {
+	return self.level
+}
+
+// Returns the number of points enabled in the node.
+func (self *Node) GetPopulation() int {
+	return self.population
+}
+
+// Returns the four quadrants of the node: north-west, north-east, south-west and south-east.
+// The nodes of level 0 have not quadrants.
+func (self *Node) GetQuadrants() (*Node, *Node, *Node, *Node) {
+	return self.nw, self.ne, self.sw, self.se
+}
+
+// Checks if the node of level 0 is enabled.
+func (self *Node) IsEnabled() bool {
+	return self == onLeaf
+}
+
+// Table with the canonical nodes.
+type nodeCache struct {
+	nodes map[nodeKey]*Node
+
+	// Empty node of each level.
+	empty []*Node
+}
+
+func newNodeCache() *nodeCache {
+	return &nodeCache{nodes: map[nodeKey]*Node{}, empty: []*Node{offLeaf}}
+}
+
+// Returns the canonical node with the quadrants `nw`, `ne`, `sw` and `se`.
+func (self *nodeCache) join(nw, ne, sw, se *Node) *Node {
+	key := nodeKey{nw, ne, sw, se}
+
+	if n, ok := self.nodes[key]; ok {
+		return n
+	}
+
+	population := nw.population + ne.population + sw.population + se.population
+	n := &Node{level: nw.level + 1, nw: nw, ne: ne, sw: sw, se: se, population: population}
+	self.nodes[key] = n
+	return n
+}
+
+// Returns the node of level `level` without points enabled.
+func (self *nodeCache) getEmpty(level uint) *Node {
+	for uint(len(self.empty)) <= level {
+		e := self.empty[len(self.empty)-1]
+		self.empty = append(self.empty, self.join(e, e, e, e))
+	}
+
+	return self.empty[level]
+}
+
+// Returns the node `n` with the level increased by one. The node `n` is in the center of the
+// new node and the rest of points are disabled.
+func (self *nodeCache) expand(n *Node) *Node {
+	e := self.getEmpty(n.level - 1)
+	return self.join(
+		self.join(e, e, e, n.nw),
+		self.join(e, e, n.ne, e),
+		self.join(e, n.sw, e, e),
+		self.join(n.se, e, e, e))
+}
+
+// Returns the node of level `level - 1` in the center of the node `n`.
+func (self *nodeCache) center(n *Node) *Node {
+	return self.join(n.nw.se, n.ne.sw, n.sw.ne, n.se.nw)
+}
+
+// Returns the number of nodes in the table.
+func (self *nodeCache) size() int {
+	return len(self.nodes)
+}
+
+// Makes a new table only with the nodes used by `root`. The memoised cycles are removed.
+func (self *nodeCache) collect(root *Node) {
+	nodes := make(map[nodeKey]*Node, len(self.nodes)/2)
+
+	var keep func(n *Node)
+	keep = func(n *Node) {
+		if n.level == 0 {
+			return
+		}
+
+		key := nodeKey{n.nw, n.ne, n.sw, n.se}
+		if _, ok := nodes[key]; ok {
+			return
+		}
+
+		n.next = nil
+		nodes[key] = n
+		keep(n.nw)
+		keep(n.ne)
+		keep(n.sw)
+		keep(n.se)
+	}
+
+	keep(root)
+	for _, e := range self.empty {
+		keep(e)
+	}
+
+	self.nodes = nodes
+}
@@ -0,0 +1,109 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// Boundary mode of the matrix. It defines what there is beyond the matrix edges.
+type Boundary int
+
+const (
+	// The points beyond the edges are always disabled.
+	BOUNDARY_DEAD Boundary = iota
+
+	// Torus: the left edge is joined to the right edge and the top edge to the bottom edge.
+	BOUNDARY_TORUS
+
+	// Klein bottle: as the torus, but the top and bottom edges are joined with a twist,
+	// so crossing them mirrors the horizontal position.
+	BOUNDARY_KLEIN
+
+	// Horizontal cylinder: the left edge is joined to the right edge.
+	// The top and bottom edges are dead.
+	BOUNDARY_CYLINDER_X
+
+	// Vertical cylinder: the top edge is joined to the bottom edge.
+	// The left and right edges are dead.
+	BOUNDARY_CYLINDER_Y
+)
+
+// Names of the boundary modes.
+var boundaryNames map[Boundary]string = map[Boundary]string{
+	BOUNDARY_DEAD:       "dead",
+	BOUNDARY_TORUS:      "torus",
+	BOUNDARY_KLEIN:      "klein",
+	BOUNDARY_CYLINDER_X: "cylinder-x",
+	BOUNDARY_CYLINDER_Y: "cylinder-y",
+}
+
+// Returns the boundary mode with the name `name`.
+// Returns an error whether the name is unknown.
+func ParseBoundary(name string) (Boundary, error) {
+	for b, n := range boundaryNames {
+		if n == name {
+			return b, nil
+		}
+	}
+
+	return BOUNDARY_DEAD, InvalidBoundaryError(name)
+}
+
+// Checks if the boundary mode is valid.
+func (self Boundary) IsValid() bool {
+	_, ok := boundaryNames[self]
+	return ok
+}
+
+// Returns true if the boundary joins the left and right edges.
+func (self Boundary) WrapsX() bool {
+	return self == BOUNDARY_TORUS || self == BOUNDARY_KLEIN || self == BOUNDARY_CYLINDER_X
+}
+
+// Returns true if the boundary joins the top and bottom edges.
+func (self Boundary) WrapsY() bool {
+	return self == BOUNDARY_TORUS || self == BOUNDARY_KLEIN || self == BOUNDARY_CYLINDER_Y
+}
+
+func (self Boundary) String() string {
+	if name, ok := boundaryNames[self]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("boundary(%d)", int(self))
+}
+
+// Returns the floor division of `a` by `b` and the modulo (always positive).
+func floorDivMod(a, b int) (int, int) {
+	q, r := a/b, a%b
+	if r < 0 {
+		q--
+		r += b
+	}
+
+	return q, r
+}
+
+// Maps the position `x`, `y`, that can be outside of a matrix of size `width`x`height`,
+// to a position inside of it using the boundary mode `boundary`.
+// The third value returned is false when the position falls beyond a dead edge.
+func wrap(boundary Boundary, width, height, x, y int) (int, int, bool) {
+	if boundary.WrapsY() {
+		var turns int
+		turns, y = floorDivMod(y, height)
+
+		if boundary == BOUNDARY_KLEIN && turns%2 != 0 {
+			// Crossing the twisted edges mirrors the horizontal position.
+			x = width - 1 - x
+		}
+	}
+
+	if boundary.WrapsX() {
+		_, x = floorDivMod(x, width)
+	}
+
+	if x < 0 || y < 0 || x >= width || y >= height {
+		return x, y, false
+	}
+
+	return x, y, true
+}
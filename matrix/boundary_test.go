@@ -0,0 +1,68 @@
+package matrix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type wrapTest struct {
+	boundary Boundary
+	position [2]int
+	wrapped  [2]int
+	inside   bool
+}
+
+// Test the function ParseBoundary.
+func TestParseBoundary(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, b := range []Boundary{
+		BOUNDARY_DEAD,
+		BOUNDARY_TORUS,
+		BOUNDARY_KLEIN,
+		BOUNDARY_CYLINDER_X,
+		BOUNDARY_CYLINDER_Y,
+	} {
+		parsed, err := ParseBoundary(b.String())
+		assert.Equal(err, nil, "There is an error.")
+		assert.Equal(parsed, b, fmt.Sprintf("Invalid boundary %s.", b))
+	}
+
+	_, err := ParseBoundary("sphere")
+	assert.Equal(err, InvalidBoundaryError("sphere"), "The error does not match.")
+}
+
+// Test the function Wrap in all the boundary modes.
+func TestWrap(t *testing.T) {
+	assert := assert.New(t)
+	tests := []wrapTest{
+		{BOUNDARY_DEAD, [2]int{5, 5}, [2]int{5, 5}, true},
+		{BOUNDARY_DEAD, [2]int{-1, 5}, [2]int{-1, 5}, false},
+		{BOUNDARY_DEAD, [2]int{5, min}, [2]int{5, min}, false},
+		{BOUNDARY_TORUS, [2]int{-1, -1}, [2]int{min - 1, min - 1}, true},
+		{BOUNDARY_TORUS, [2]int{min, min + 2}, [2]int{0, 2}, true},
+		{BOUNDARY_TORUS, [2]int{3, 4}, [2]int{3, 4}, true},
+		{BOUNDARY_KLEIN, [2]int{-1, 5}, [2]int{min - 1, 5}, true},
+		{BOUNDARY_KLEIN, [2]int{2, -1}, [2]int{min - 3, min - 1}, true},
+		{BOUNDARY_KLEIN, [2]int{2, min}, [2]int{min - 3, 0}, true},
+		{BOUNDARY_KLEIN, [2]int{-1, -1}, [2]int{0, min - 1}, true},
+		{BOUNDARY_KLEIN, [2]int{2, 2 * min}, [2]int{2, 0}, true},
+		{BOUNDARY_CYLINDER_X, [2]int{-1, 5}, [2]int{min - 1, 5}, true},
+		{BOUNDARY_CYLINDER_X, [2]int{5, -1}, [2]int{5, -1}, false},
+		{BOUNDARY_CYLINDER_Y, [2]int{5, -1}, [2]int{5, min - 1}, true},
+		{BOUNDARY_CYLINDER_Y, [2]int{min, 5}, [2]int{min, 5}, false},
+	}
+
+	for _, test := range tests {
+		m, _ := NewWithBoundary(min, min, test.boundary)
+		x, y, inside := m.Wrap(test.position[0], test.position[1])
+		message := fmt.Sprintf("Invalid wrap of %dx%d in %s.", test.position[0], test.position[1], test.boundary)
+
+		assert.Equal(inside, test.inside, message)
+		if inside {
+			assert.Equal([2]int{x, y}, test.wrapped, message)
+		}
+	}
+}
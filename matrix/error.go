@@ -26,7 +26,52 @@ func (self *invalidSizeError) Error() string {
 	return fmt.Sprintf(message, self[0], self[1], MINIMUM_SIZE, MINIMUM_SIZE)
 }
 
-func OutIndexError(m *Matrix, x, y int) error {
+type sizeMismatchError [4]int
+
+func (self *sizeMismatchError) Error() string {
+	message := "The matrix sizes (%dx%d) and (%dx%d) are different."
+	return fmt.Sprintf(message, self[0], self[1], self[2], self[3])
+}
+
+type invalidBoundaryError string
+
+func (self *invalidBoundaryError) Error() string {
+	message := "The boundary mode \"%s\" is invalid."
+	return fmt.Sprintf(message, string(*self))
+}
+
+type invalidStorageError string
+
+func (self *invalidStorageError) Error() string {
+	message := "The storage \"%s\" is invalid."
+	return fmt.Sprintf(message, string(*self))
+}
+
+type invalidTopologyError string
+
+func (self *invalidTopologyError) Error() string {
+	message := "The topology \"%s\" is invalid."
+	return fmt.Sprintf(message, string(*self))
+}
+
+type topologyError struct {
+	topology Topology
+	reason   string
+}
+
+func (self *topologyError) Error() string {
+	message := "The %s topology is not allowed: %s."
+	return fmt.Sprintf(message, self.topology, self.reason)
+}
+
+type invalidValueError int
+
+func (self *invalidValueError) Error() string {
+	message := "The point value %d is invalid."
+	return fmt.Sprintf(message, int(*self))
+}
+
+func OutIndexError(m Universe, x, y int) error {
 	err := outIndexError{}
 
 	err.size[0] = m.GetWidth()
@@ -40,3 +85,33 @@ func OutIndexError(m *Matrix, x, y int) error {
 func InvalidSizeError(width, height int) error {
 	return &invalidSizeError{width, height}
 }
+
+func InvalidBoundaryError(name string) error {
+	err := invalidBoundaryError(name)
+	return &err
+}
+
+func InvalidStorageError(name string) error {
+	err := invalidStorageError(name)
+	return &err
+}
+
+func SizeMismatchError(m, other Universe) error {
+	w, h := m.GetSize()
+	ow, oh := other.GetSize()
+	return &sizeMismatchError{w, h, ow, oh}
+}
+
+func InvalidTopologyError(name string) error {
+	err := invalidTopologyError(name)
+	return &err
+}
+
+func TopologyError(topology Topology, reason string) error {
+	return &topologyError{topology, reason}
+}
+
+func InvalidValueError(value int) error {
+	err := invalidValueError(value)
+	return &err
+}
@@ -10,6 +10,10 @@ const MATRIX_POINT_DISABLED int = 0
 // Value of the enabled points in the matrix.
 const MATRIX_POINT_ENABLED int = 1
 
+// Value of the first dying state of the Generations rules. The points enabled that do not
+// survive pass through the dying states, one per cycle, before getting disabled.
+const MATRIX_POINT_DYING int = 2
+
 // Matrix minum size.
 const MINIMUM_SIZE int = 10
 
@@ -24,8 +28,14 @@ type Matrix struct {
 	// Slice vertical size.
 	height int
 
-	// points enabled.
-	enabled int
+	// Number of points in each state. The index is the value of the points.
+	counts []int
+
+	// What there is beyond the matrix edges.
+	boundary Boundary
+
+	// Shape of the cells and adjacents of the points.
+	topology Topology
 }
 
 // Check if the `x`, `y` position are inside range of the matrix.
@@ -57,46 +67,72 @@ func createEmptyMatrixArray(width int, height int) (m [][]int) {
 // `width`and `height` params are used to define the matrix size.
 // It returns and error whether width or height are negative numbers.
 func New(width, height int) (*Matrix, error) {
+	return NewWithBoundary(width, height, BOUNDARY_DEAD)
+}
+
+// Same as `New` but the matrix uses the boundary mode `boundary`.
+// It returns an error whether the boundary mode is invalid.
+func NewWithBoundary(width, height int, boundary Boundary) (*Matrix, error) {
+	return NewWithTopology(width, height, boundary, TOPOLOGY_SQUARE)
+}
+
+// Same as `NewWithBoundary` but the matrix uses the topology `topology`.
+// It returns an error whether the topology is invalid or it can not use the boundary mode.
+func NewWithTopology(width, height int, boundary Boundary, topology Topology) (*Matrix, error) {
 	if width < MINIMUM_SIZE || height < MINIMUM_SIZE {
 		err := InvalidSizeError(width, height)
 		return nil, err
 	}
 
-	m := &Matrix{createEmptyMatrixArray(width, height), width, height, 0}
+	if !boundary.IsValid() {
+		return nil, InvalidBoundaryError(boundary.String())
+	}
+
+	if !topology.IsValid() {
+		return nil, InvalidTopologyError(topology.String())
+	}
+
+	if err := topology.checkBoundary(boundary, width, height); err != nil {
+		return nil, err
+	}
+
+	counts := []int{width * height, 0}
+	m := &Matrix{createEmptyMatrixArray(width, height), width, height, counts, boundary, topology}
 	return m, nil
 }
 
-// Enable the point of the position `x`, `y` of the matrix stored in `self`.
-// Whether the position is invalid returns an error.
-func (self *Matrix) EnablePoint(x, y int) (e error) {
-	e = checkRange(self, x, y)
-	if e != nil {
+// Sets the value `value` in the point of the position `x`, `y` of the matrix stored in `self`.
+// The values greater than `MATRIX_POINT_ENABLED` are the dying states of the Generations rules.
+// Whether the position or the value are invalid returns an error.
+func (self *Matrix) SetPoint(x, y, value int) error {
+	if e := checkRange(self, x, y); e != nil {
 		return e
 	}
 
-	if self.matrix[x][y] != MATRIX_POINT_ENABLED {
-		self.matrix[x][y] = MATRIX_POINT_ENABLED
-		self.enabled++
+	if value < MATRIX_POINT_DISABLED {
+		return InvalidValueError(value)
+	}
+
+	for len(self.counts) <= value {
+		self.counts = append(self.counts, 0)
 	}
 
+	self.counts[self.matrix[x][y]]--
+	self.counts[value]++
+	self.matrix[x][y] = value
 	return nil
 }
 
+// Enable the point of the position `x`, `y` of the matrix stored in `self`.
+// Whether the position is invalid returns an error.
+func (self *Matrix) EnablePoint(x, y int) (e error) {
+	return self.SetPoint(x, y, MATRIX_POINT_ENABLED)
+}
+
 // Disable the point of the position `x`, `y` of the matrix stored in `self`.
 // Whether the position is invalid returns an error.
 func (self *Matrix) DisablePoint(x, y int) (e error) {
-	e = checkRange(self, x, y)
-
-	if e != nil {
-		return e
-	}
-
-	if self.matrix[x][y] != MATRIX_POINT_DISABLED {
-		self.matrix[x][y] = MATRIX_POINT_DISABLED
-		self.enabled--
-	}
-
-	return nil
+	return self.SetPoint(x, y, MATRIX_POINT_DISABLED)
 }
 
 // Checks if the point of the position `x`, `y` of the matrix stored in `self` is enabled.
@@ -117,6 +153,12 @@ func (self *Matrix) Reset() {
 			self.matrix[i][j] = MATRIX_POINT_DISABLED
 		}
 	}
+
+	for i := range self.counts {
+		self.counts[i] = 0
+	}
+
+	self.counts[MATRIX_POINT_DISABLED] = self.width * self.height
 }
 
 // Returns the value of the position `x`, `y` of the matrix stored in `self`.
@@ -145,12 +187,130 @@ func (self *Matrix) GetSize() (int, int) {
 	return self.width, self.height
 }
 
+// Returns the boundary mode of the matrix.
+func (self *Matrix) GetBoundary() Boundary {
+	return self.boundary
+}
+
+// Returns the topology of the matrix.
+func (self *Matrix) GetTopology() Topology {
+	return self.topology
+}
+
+// Maps the position `x`, `y`, that can be outside of the matrix, to a position inside
+// of it using the matrix boundary mode.
+// The third value returned is false when the position falls beyond a dead edge.
+func (self *Matrix) Wrap(x, y int) (int, int, bool) {
+	return wrap(self.boundary, self.width, self.height, x, y)
+}
+
+// The matrix is bounded.
+func (self *Matrix) IsBounded() bool {
+	return true
+}
+
+// Returns the smallest rectangle with all the points enabled.
+// The second value is false when there are not points enabled.
+func (self *Matrix) GetBoundingBox() (Rect, bool) {
+	box, found := Rect{}, false
+
+	self.ForEachEnabled(func(x, y int) {
+		if !found {
+			box, found = Rect{x, y, x, y}, true
+		}
+
+		box = box.Extend(x, y)
+	})
+
+	return box, found
+}
+
+// Calls the function `callback` with the position of each point enabled,
+// column by column.
+func (self *Matrix) ForEachEnabled(callback func(x, y int)) {
+	for i := 0; i < self.width; i++ {
+		for j := 0; j < self.height; j++ {
+			if self.matrix[i][j] == MATRIX_POINT_ENABLED {
+				callback(i, j)
+			}
+		}
+	}
+}
+
+// Calls the function `callback` with the position and the value of each point not disabled,
+// column by column.
+func (self *Matrix) ForEachPoint(callback func(x, y, value int)) {
+	for i := 0; i < self.width; i++ {
+		for j := 0; j < self.height; j++ {
+			if value := self.matrix[i][j]; value != MATRIX_POINT_DISABLED {
+				callback(i, j, value)
+			}
+		}
+	}
+}
+
 // Get the points enabled.
 func (self *Matrix) GetPointsEnabled() int {
-	return self.enabled
+	return self.counts[MATRIX_POINT_ENABLED]
+}
+
+// Returns the number of points with the value `value`.
+func (self *Matrix) GetPointsWithValue(value int) int {
+	if value < 0 || value >= len(self.counts) {
+		return 0
+	}
+
+	return self.counts[value]
+}
+
+// Returns the points of the column `x`. The slice is shared with the matrix, so it allows
+// read and write the points directly. Call to `Recount` after modifying the columns.
+// Whether the column is invalid returns an error.
+func (self *Matrix) GetColumn(x int) ([]int, error) {
+	if x < 0 || x >= self.width {
+		return nil, OutIndexError(self, x, 0)
+	}
+
+	return self.matrix[x], nil
+}
+
+// Recalculates the number of points in each state. It is needed after modifying the columns
+// directly.
+func (self *Matrix) Recount() {
+	for i := range self.counts {
+		self.counts[i] = 0
+	}
+
+	for i := 0; i < self.width; i++ {
+		for j := 0; j < self.height; j++ {
+			value := self.matrix[i][j]
+			for len(self.counts) <= value {
+				self.counts = append(self.counts, 0)
+			}
+
+			self.counts[value]++
+		}
+	}
+}
+
+// Exchanges the points of the matrix with the points of the matrix `other`.
+// It returns an error whether the matrices sizes are different.
+func (self *Matrix) Swap(other *Matrix) error {
+	if self.width != other.width || self.height != other.height {
+		return SizeMismatchError(self, other)
+	}
+
+	self.matrix, other.matrix = other.matrix, self.matrix
+	self.counts, other.counts = other.counts, self.counts
+	return nil
 }
 
 func (matrix Matrix) String() string {
+	if matrix.topology != TOPOLOGY_SQUARE {
+		msg := "Matrix (%dx%d, %s)"
+		return fmt.Sprintf(msg, matrix.width, matrix.height, matrix.topology)
+	}
+
 	msg := "Matrix (%dx%d)"
 	return fmt.Sprintf(msg, matrix.width, matrix.height)
 }
@@ -207,3 +207,98 @@ func TestGetPointsEnabledFunc(t *testing.T) {
 	assert.Equal(m.GetPointsEnabled(), 0, "Invalid points enabled")
 
 }
+
+// Test the function NewWithBoundary.
+func TestNewMatrixWithBoundary(t *testing.T) {
+	assert := assert.New(t)
+
+	m, err := NewWithBoundary(min, min, BOUNDARY_TORUS)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(m.GetBoundary(), BOUNDARY_TORUS, "Invalid boundary.")
+
+	m, _ = New(min, min)
+	assert.Equal(m.GetBoundary(), BOUNDARY_DEAD, "The default boundary is not dead.")
+
+	_, err = NewWithBoundary(min, min, Boundary(-1))
+	assert.Equal(err, InvalidBoundaryError("boundary(-1)"), "The error does not match.")
+}
+
+// Test the function Reset clears the points counter.
+func TestResetPointsEnabled(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := New(min, min)
+
+	m.EnablePoint(1, 1)
+	m.EnablePoint(2, 2)
+	m.Reset()
+	assert.Equal(m.GetPointsEnabled(), 0, "Invalid points enabled")
+}
+
+// Test the functions GetColumn, Recount and Swap.
+func TestMatrixColumnsAndSwap(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := New(min, min)
+	other, _ := New(min, min)
+
+	column, err := m.GetColumn(2)
+	assert.Equal(err, nil, "There is an error.")
+	column[3] = MATRIX_POINT_ENABLED
+	column[4] = MATRIX_POINT_ENABLED
+	m.Recount()
+	assert.Equal(m.GetPointsEnabled(), 2, "Invalid points enabled")
+
+	_, err = m.GetColumn(min)
+	assert.Equal(err, OutIndexError(m, min, 0), "The error does not match.")
+
+	other.EnablePoint(0, 0)
+	assert.Equal(m.Swap(other), nil, "There is an error.")
+	assert.Equal(m.GetPointsEnabled(), 1, "Invalid points enabled")
+	assert.Equal(other.GetPointsEnabled(), 2, "Invalid points enabled")
+
+	enabled, _ := other.IsEnabled(2, 4)
+	assert.Equal(enabled, true, "The point 2x4 is disabled.")
+
+	bigger, _ := New(min+1, min)
+	assert.Equal(m.Swap(bigger), SizeMismatchError(m, bigger), "The error does not match.")
+}
+
+// Test the function SetPoint and the number of points in each state.
+func TestSetPointStates(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := New(min, min)
+
+	assert.Equal(m.SetPoint(1, 1, MATRIX_POINT_DYING+1), nil, "There is an error.")
+	m.SetPoint(2, 2, MATRIX_POINT_DYING)
+	m.SetPoint(3, 3, MATRIX_POINT_ENABLED)
+	assert.Equal(m.GetPointsEnabled(), 1, "Invalid points enabled.")
+	assert.Equal(m.GetPointsWithValue(MATRIX_POINT_DYING), 1, "Invalid dying points.")
+	assert.Equal(m.GetPointsWithValue(MATRIX_POINT_DYING+1), 1, "Invalid points in the second dying state.")
+	assert.Equal(m.GetPointsWithValue(MATRIX_POINT_DISABLED), min*min-3, "Invalid points disabled.")
+	assert.Equal(m.GetPointsWithValue(10), 0, "Invalid points of an unused value.")
+
+	enabled, _ := m.IsEnabled(2, 2)
+	assert.Equal(enabled, false, "The dying point is enabled.")
+
+	values := map[[2]int]int{}
+	m.ForEachPoint(func(x, y, value int) {
+		values[[2]int{x, y}] = value
+	})
+	assert.Equal(values, map[[2]int]int{{1, 1}: 3, {2, 2}: 2, {3, 3}: 1}, "Invalid points.")
+
+	m.SetPoint(1, 1, MATRIX_POINT_DISABLED)
+	assert.Equal(m.GetPointsWithValue(MATRIX_POINT_DYING+1), 0, "The point is not removed of its state.")
+
+	assert.Equal(m.SetPoint(0, 0, -1), InvalidValueError(-1), "The error does not match.")
+	assert.Equal(m.SetPoint(min, 0, 1), OutIndexError(m, min, 0), "The error does not match.")
+
+	// Recount the states modifying the columns.
+	column, _ := m.GetColumn(5)
+	column[5] = 4
+	m.Recount()
+	assert.Equal(m.GetPointsWithValue(4), 1, "Invalid points recounted.")
+	assert.Equal(m.GetPointsWithValue(MATRIX_POINT_DYING), 1, "Invalid dying points recounted.")
+
+	m.Reset()
+	assert.Equal(m.GetPointsWithValue(4), 0, "The states are not reset.")
+	assert.Equal(m.GetPointsWithValue(MATRIX_POINT_DISABLED), min*min, "Invalid points disabled after reset.")
+}
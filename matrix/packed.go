@@ -0,0 +1,250 @@
+package matrix
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Bits per word of the packed matrix.
+const WORD_SIZE int = 64
+
+// Bounded universe that stores each point in a bit.
+// The points are saved row by row; the point `x`, `y` is the bit `x % 64` of the word `x / 64`
+// of the row `y`.
+type Packed struct {
+	// Words with the points.
+	words []uint64
+
+	// Words per row.
+	stride int
+
+	// Horizontal size.
+	width int
+
+	// Vertical size.
+	height int
+
+	// points enabled.
+	enabled int
+
+	// What there is beyond the matrix edges.
+	boundary Boundary
+}
+
+// Creates and returns a new packed matrix of size `width`x`height` with all the points disabled.
+// It returns an error whether the size or the boundary mode are invalid.
+func NewPacked(width, height int, boundary Boundary) (*Packed, error) {
+	if width < MINIMUM_SIZE || height < MINIMUM_SIZE {
+		return nil, InvalidSizeError(width, height)
+	}
+
+	if !boundary.IsValid() {
+		return nil, InvalidBoundaryError(boundary.String())
+	}
+
+	stride := (width + WORD_SIZE - 1) / WORD_SIZE
+	m := &Packed{make([]uint64, stride*height), stride, width, height, 0, boundary}
+	return m, nil
+}
+
+// Returns the index of the word and the bit mask of the point `x`, `y`.
+// Whether the position is invalid returns an error.
+func (self *Packed) locate(x, y int) (int, uint64, error) {
+	if x < 0 || y < 0 || x >= self.width || y >= self.height {
+		return 0, 0, OutIndexError(self, x, y)
+	}
+
+	return y*self.stride + x/WORD_SIZE, uint64(1) << uint(x%WORD_SIZE), nil
+}
+
+// Enable the point of the position `x`, `y`.
+// Whether the position is invalid returns an error.
+func (self *Packed) EnablePoint(x, y int) error {
+	i, mask, err := self.locate(x, y)
+	if err != nil {
+		return err
+	}
+
+	if self.words[i]&mask == 0 {
+		self.words[i] |= mask
+		self.enabled++
+	}
+
+	return nil
+}
+
+// Disable the point of the position `x`, `y`.
+// Whether the position is invalid returns an error.
+func (self *Packed) DisablePoint(x, y int) error {
+	i, mask, err := self.locate(x, y)
+	if err != nil {
+		return err
+	}
+
+	if self.words[i]&mask != 0 {
+		self.words[i] &^= mask
+		self.enabled--
+	}
+
+	return nil
+}
+
+// Checks if the point of the position `x`, `y` is enabled.
+// Whether the position is invalid returns error.
+func (self *Packed) IsEnabled(x, y int) (bool, error) {
+	i, mask, err := self.locate(x, y)
+	if err != nil {
+		return false, err
+	}
+
+	return self.words[i]&mask != 0, nil
+}
+
+// Returns the value of the position `x`, `y`.
+// Whether position is invalid returns an error as second element.
+func (self *Packed) GetPoint(x, y int) (int, error) {
+	enabled, err := self.IsEnabled(x, y)
+	if err != nil {
+		return 0, err
+	}
+
+	if enabled {
+		return MATRIX_POINT_ENABLED, nil
+	}
+
+	return MATRIX_POINT_DISABLED, nil
+}
+
+// Disable all points in the matrix.
+func (self *Packed) Reset() {
+	for i := range self.words {
+		self.words[i] = 0
+	}
+
+	self.enabled = 0
+}
+
+// Returns the *width* of the matrix.
+func (self *Packed) GetWidth() int {
+	return self.width
+}
+
+// Returns the *height* of the matrix.
+func (self *Packed) GetHeight() int {
+	return self.height
+}
+
+// returns the width and height of the matrix.
+func (self *Packed) GetSize() (int, int) {
+	return self.width, self.height
+}
+
+// Get the points enabled.
+func (self *Packed) GetPointsEnabled() int {
+	return self.enabled
+}
+
+// Returns the boundary mode of the matrix.
+func (self *Packed) GetBoundary() Boundary {
+	return self.boundary
+}
+
+// Maps the position `x`, `y`, that can be outside of the matrix, to a position inside
+// of it using the matrix boundary mode.
+// The third value returned is false when the position falls beyond a dead edge.
+func (self *Packed) Wrap(x, y int) (int, int, bool) {
+	return wrap(self.boundary, self.width, self.height, x, y)
+}
+
+// The packed matrix is bounded.
+func (self *Packed) IsBounded() bool {
+	return true
+}
+
+// Returns the smallest rectangle with all the points enabled.
+// The second value is false when there are not points enabled.
+func (self *Packed) GetBoundingBox() (Rect, bool) {
+	box, found := Rect{}, false
+
+	for y := 0; y < self.height; y++ {
+		row := self.words[y*self.stride : (y+1)*self.stride]
+
+		for i, word := range row {
+			if word == 0 {
+				continue
+			}
+
+			minX := i*WORD_SIZE + bits.TrailingZeros64(word)
+			maxX := i*WORD_SIZE + WORD_SIZE - 1 - bits.LeadingZeros64(word)
+
+			if !found {
+				box, found = Rect{minX, y, maxX, y}, true
+			}
+
+			box = box.Extend(minX, y).Extend(maxX, y)
+		}
+	}
+
+	return box, found
+}
+
+// Calls the function `callback` with the position of each point enabled, row by row.
+func (self *Packed) ForEachEnabled(callback func(x, y int)) {
+	for y := 0; y < self.height; y++ {
+		row := self.words[y*self.stride : (y+1)*self.stride]
+
+		for i, word := range row {
+			for word != 0 {
+				b := bits.TrailingZeros64(word)
+				callback(i*WORD_SIZE+b, y)
+				word &= word - 1
+			}
+		}
+	}
+}
+
+// Returns the words of the row `y`. The slice is shared with the matrix, so it allows
+// read and write the points directly; the bits beyond the matrix width must be zero.
+// Call to `Recount` after modifying the rows.
+// Whether the row is invalid returns an error.
+func (self *Packed) GetRow(y int) ([]uint64, error) {
+	if y < 0 || y >= self.height {
+		return nil, OutIndexError(self, 0, y)
+	}
+
+	return self.words[y*self.stride : (y+1)*self.stride], nil
+}
+
+// Returns the mask with the valid bits of the last word of each row.
+func (self *Packed) GetLastWordMask() uint64 {
+	if r := self.width % WORD_SIZE; r != 0 {
+		return uint64(1)<<uint(r) - 1
+	}
+
+	return ^uint64(0)
+}
+
+// Recalculates the number of points enabled. It is needed after modifying the rows directly.
+func (self *Packed) Recount() {
+	self.enabled = 0
+	for _, word := range self.words {
+		self.enabled += bits.OnesCount64(word)
+	}
+}
+
+// Exchanges the points of the matrix with the points of the matrix `other`.
+// It returns an error whether the matrices sizes are different.
+func (self *Packed) Swap(other *Packed) error {
+	if self.width != other.width || self.height != other.height {
+		return SizeMismatchError(self, other)
+	}
+
+	self.words, other.words = other.words, self.words
+	self.enabled, other.enabled = other.enabled, self.enabled
+	return nil
+}
+
+func (self Packed) String() string {
+	msg := "Packed matrix (%dx%d)"
+	return fmt.Sprintf(msg, self.width, self.height)
+}
@@ -0,0 +1,173 @@
+package matrix
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the packed matrix size when it is made.
+func TestNewPacked(t *testing.T) {
+	assert := assert.New(t)
+
+	m, err := NewPacked(min, min, BOUNDARY_DEAD)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(len(m.words), min, "Invalid number of words.")
+
+	m, _ = NewPacked(130, min, BOUNDARY_DEAD)
+	assert.Equal(len(m.words), 3*min, "Invalid number of words.")
+
+	// 10000x10000 points only need 12.5MB.
+	m, _ = NewPacked(10000, 10000, BOUNDARY_DEAD)
+	assert.Equal(len(m.words)*8, 157*10000*8, "Invalid number of words.")
+
+	_, err = NewPacked(-1, -1, BOUNDARY_DEAD)
+	assert.Equal(err, InvalidSizeError(-1, -1), "The error does not match.")
+
+	_, err = NewPacked(min, min, Boundary(-1))
+	assert.Equal(err, InvalidBoundaryError("boundary(-1)"), "The error does not match.")
+}
+
+// Test the errors when pass an invalid position.
+func TestPackedPointError(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := NewPacked(min, min, BOUNDARY_DEAD)
+
+	for _, p := range [][2]int{{min, min}, {-1, -1}, {min, 0}, {0, min}} {
+		err := m.EnablePoint(p[0], p[1])
+		assert.Equal(err, OutIndexError(m, p[0], p[1]), "The error does not match.")
+
+		err = m.DisablePoint(p[0], p[1])
+		assert.Equal(err, OutIndexError(m, p[0], p[1]), "The error does not match.")
+
+		enabled, err := m.IsEnabled(p[0], p[1])
+		assert.Equal(enabled, false, "The value is not false.")
+		assert.Equal(err, OutIndexError(m, p[0], p[1]), "The error does not match.")
+
+		value, err := m.GetPoint(p[0], p[1])
+		assert.Equal(value, 0, "The value is not zero.")
+		assert.Equal(err, OutIndexError(m, p[0], p[1]), "The error does not match.")
+	}
+}
+
+// Test the packed matrix has the same behaviour than the dense matrix.
+func TestPackedSameAsDense(t *testing.T) {
+	assert := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+	w, h := 150, 70
+	dense, _ := New(w, h)
+	packed, _ := NewPacked(w, h, BOUNDARY_DEAD)
+
+	for i := 0; i < 5000; i++ {
+		x, y := r.Intn(w), r.Intn(h)
+
+		if r.Intn(3) == 0 {
+			dense.DisablePoint(x, y)
+			packed.DisablePoint(x, y)
+		} else {
+			dense.EnablePoint(x, y)
+			packed.EnablePoint(x, y)
+		}
+	}
+
+	assert.Equal(packed.GetPointsEnabled(), dense.GetPointsEnabled(), "Invalid points enabled.")
+
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			de, _ := dense.GetPoint(x, y)
+			pe, _ := packed.GetPoint(x, y)
+			assert.Equal(pe, de, fmt.Sprintf("The point %dx%d is different.", x, y))
+		}
+	}
+
+	dbox, _ := dense.GetBoundingBox()
+	pbox, _ := packed.GetBoundingBox()
+	assert.Equal(pbox, dbox, "Invalid bounding box.")
+
+	visited := 0
+	packed.ForEachEnabled(func(x, y int) {
+		enabled, _ := dense.IsEnabled(x, y)
+		assert.Equal(enabled, true, fmt.Sprintf("The point %dx%d is disabled.", x, y))
+		visited++
+	})
+	assert.Equal(visited, dense.GetPointsEnabled(), "Invalid points visited.")
+
+	packed.Reset()
+	assert.Equal(packed.GetPointsEnabled(), 0, "Invalid points enabled.")
+	_, ok := packed.GetBoundingBox()
+	assert.Equal(ok, false, "There is a bounding box without points.")
+}
+
+// Size of the matrices used in the benchmarks.
+const benchSize int = 2048
+
+func benchmarkNew(b *testing.B, storage Storage) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewUniverse(storage, benchSize, benchSize, BOUNDARY_DEAD)
+	}
+}
+
+func benchmarkToggle(b *testing.B, storage Storage) {
+	u, _ := NewUniverse(storage, benchSize, benchSize, BOUNDARY_DEAD)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		x, y := (i*7)%benchSize, (i*13)%benchSize
+		u.EnablePoint(x, y)
+		u.DisablePoint(y, x)
+	}
+}
+
+func benchmarkScan(b *testing.B, storage Storage) {
+	u, _ := NewUniverse(storage, benchSize, benchSize, BOUNDARY_DEAD)
+	for i := 0; i < benchSize; i++ {
+		u.EnablePoint(i, (i*31)%benchSize)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for y := 0; y < benchSize; y++ {
+			for x := 0; x < benchSize; x++ {
+				u.IsEnabled(x, y)
+			}
+		}
+	}
+}
+
+func BenchmarkNewDense(b *testing.B)     { benchmarkNew(b, STORAGE_DENSE) }
+func BenchmarkNewPacked(b *testing.B)    { benchmarkNew(b, STORAGE_PACKED) }
+func BenchmarkToggleDense(b *testing.B)  { benchmarkToggle(b, STORAGE_DENSE) }
+func BenchmarkTogglePacked(b *testing.B) { benchmarkToggle(b, STORAGE_PACKED) }
+func BenchmarkScanDense(b *testing.B)    { benchmarkScan(b, STORAGE_DENSE) }
+func BenchmarkScanPacked(b *testing.B)   { benchmarkScan(b, STORAGE_PACKED) }
+
+// Test the functions GetRow, Recount and Swap.
+func TestPackedRowsAndSwap(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := NewPacked(70, min, BOUNDARY_DEAD)
+	other, _ := NewPacked(70, min, BOUNDARY_DEAD)
+
+	row, err := m.GetRow(3)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(len(row), 2, "Invalid row size.")
+	row[1] = 0x3f
+	m.Recount()
+	assert.Equal(m.GetPointsEnabled(), 6, "Invalid points enabled.")
+	assert.Equal(m.GetLastWordMask(), uint64(0x3f), "Invalid mask.")
+
+	enabled, _ := m.IsEnabled(69, 3)
+	assert.Equal(enabled, true, "The point 69x3 is disabled.")
+
+	_, err = m.GetRow(-1)
+	assert.Equal(err, OutIndexError(m, 0, -1), "The error does not match.")
+
+	assert.Equal(m.Swap(other), nil, "There is an error.")
+	assert.Equal(m.GetPointsEnabled(), 0, "Invalid points enabled.")
+	assert.Equal(other.GetPointsEnabled(), 6, "Invalid points enabled.")
+
+	smaller, _ := NewPacked(min, min, BOUNDARY_DEAD)
+	assert.Equal(m.Swap(smaller), SizeMismatchError(m, smaller), "The error does not match.")
+}
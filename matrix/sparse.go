@@ -0,0 +1,166 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// Unbounded universe. Only the positions of the points enabled are stored,
+// so the patterns can grow without limit and the positions can be negative.
+type Sparse struct {
+	// Hash set with the positions of the points enabled.
+	points map[[2]int]struct{}
+
+	// Bounding box of the points enabled.
+	box Rect
+
+	// False when the bounding box must be recalculated.
+	boxValid bool
+}
+
+// Creates and returns a new sparse universe without points enabled.
+func NewSparse() *Sparse {
+	return &Sparse{points: map[[2]int]struct{}{}}
+}
+
+// Enable the point of the position `x`, `y`. It never returns an error.
+func (self *Sparse) EnablePoint(x, y int) error {
+	p := [2]int{x, y}
+
+	if _, ok := self.points[p]; ok {
+		return nil
+	}
+
+	if len(self.points) == 0 {
+		self.box = Rect{x, y, x, y}
+		self.boxValid = true
+	} else if self.boxValid {
+		self.box = self.box.Extend(x, y)
+	}
+
+	self.points[p] = struct{}{}
+	return nil
+}
+
+// Disable the point of the position `x`, `y`. It never returns an error.
+func (self *Sparse) DisablePoint(x, y int) error {
+	p := [2]int{x, y}
+
+	if _, ok := self.points[p]; !ok {
+		return nil
+	}
+
+	delete(self.points, p)
+
+	// The point was in the border of the bounding box. Maybe it is smaller now.
+	box := self.box
+	if x == box.MinX || x == box.MaxX || y == box.MinY || y == box.MaxY {
+		self.boxValid = false
+	}
+
+	return nil
+}
+
+// Checks if the point of the position `x`, `y` is enabled. It never returns an error.
+func (self *Sparse) IsEnabled(x, y int) (bool, error) {
+	_, ok := self.points[[2]int{x, y}]
+	return ok, nil
+}
+
+// Returns the value of the point of the position `x`, `y`. It never returns an error.
+func (self *Sparse) GetPoint(x, y int) (int, error) {
+	if _, ok := self.points[[2]int{x, y}]; ok {
+		return MATRIX_POINT_ENABLED, nil
+	}
+
+	return MATRIX_POINT_DISABLED, nil
+}
+
+// Disable all points.
+func (self *Sparse) Reset() {
+	self.points = map[[2]int]struct{}{}
+	self.boxValid = false
+}
+
+// Returns the width of the bounding box.
+func (self *Sparse) GetWidth() int {
+	w, _ := self.GetSize()
+	return w
+}
+
+// Returns the height of the bounding box.
+func (self *Sparse) GetHeight() int {
+	_, h := self.GetSize()
+	return h
+}
+
+// Returns the width and height of the bounding box. Zero when there are not points enabled.
+func (self *Sparse) GetSize() (int, int) {
+	box, ok := self.GetBoundingBox()
+	if !ok {
+		return 0, 0
+	}
+
+	return box.GetSize()
+}
+
+// Get the points enabled.
+func (self *Sparse) GetPointsEnabled() int {
+	return len(self.points)
+}
+
+// The sparse universe has not edges. It always returns `BOUNDARY_DEAD`.
+func (self *Sparse) GetBoundary() Boundary {
+	return BOUNDARY_DEAD
+}
+
+// All the positions are valid in the sparse universe, so they are returned unchanged.
+func (self *Sparse) Wrap(x, y int) (int, int, bool) {
+	return x, y, true
+}
+
+// The sparse universe is unbounded.
+func (self *Sparse) IsBounded() bool {
+	return false
+}
+
+// Returns the smallest rectangle with all the points enabled.
+// The second value is false when there are not points enabled.
+func (self *Sparse) GetBoundingBox() (Rect, bool) {
+	if len(self.points) == 0 {
+		return Rect{}, false
+	}
+
+	if !self.boxValid {
+		first := true
+		for p := range self.points {
+			if first {
+				self.box = Rect{p[0], p[1], p[0], p[1]}
+				first = false
+			}
+
+			self.box = self.box.Extend(p[0], p[1])
+		}
+
+		self.boxValid = true
+	}
+
+	return self.box, true
+}
+
+// Calls the function `callback` with the position of each point enabled.
+// The order of the positions is undefined.
+func (self *Sparse) ForEachEnabled(callback func(x, y int)) {
+	for p := range self.points {
+		callback(p[0], p[1])
+	}
+}
+
+// Exchanges the points of the universe with the points of the universe `other`.
+func (self *Sparse) Swap(other *Sparse) {
+	*self, *other = *other, *self
+}
+
+func (self Sparse) String() string {
+	msg := "Sparse (%d points)"
+	return fmt.Sprintf(msg, len(self.points))
+}
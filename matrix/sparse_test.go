@@ -0,0 +1,103 @@
+package matrix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the functions EnablePoint, DisablePoint and IsEnabled with any position.
+func TestSparseEnablePoint(t *testing.T) {
+	assert := assert.New(t)
+	s := NewSparse()
+
+	for _, p := range [][2]int{{0, 0}, {-5, 3}, {1 << 40, -(1 << 40)}} {
+		err := s.EnablePoint(p[0], p[1])
+		assert.Equal(err, nil, "There is an error.")
+
+		enabled, err := s.IsEnabled(p[0], p[1])
+		assert.Equal(err, nil, "There is an error.")
+		assert.Equal(enabled, true, "The point is disabled.")
+
+		value, _ := s.GetPoint(p[0], p[1])
+		assert.Equal(value, MATRIX_POINT_ENABLED, "The point value is not enabled.")
+	}
+
+	assert.Equal(s.GetPointsEnabled(), 3, "Invalid points enabled.")
+
+	s.EnablePoint(-5, 3)
+	assert.Equal(s.GetPointsEnabled(), 3, "Invalid points enabled.")
+
+	s.DisablePoint(-5, 3)
+	s.DisablePoint(-5, 3)
+	enabled, _ := s.IsEnabled(-5, 3)
+	assert.Equal(enabled, false, "The point is enabled.")
+	assert.Equal(s.GetPointsEnabled(), 2, "Invalid points enabled.")
+
+	s.Reset()
+	assert.Equal(s.GetPointsEnabled(), 0, "Invalid points enabled.")
+}
+
+// Test the function GetBoundingBox.
+func TestSparseBoundingBox(t *testing.T) {
+	assert := assert.New(t)
+	s := NewSparse()
+
+	_, ok := s.GetBoundingBox()
+	assert.Equal(ok, false, "There is a bounding box without points.")
+	w, h := s.GetSize()
+	assert.Equal([2]int{w, h}, [2]int{0, 0}, "Invalid size.")
+
+	s.EnablePoint(2, 3)
+	box, ok := s.GetBoundingBox()
+	assert.Equal(ok, true, "There is not bounding box.")
+	assert.Equal(box, Rect{2, 3, 2, 3}, "Invalid bounding box.")
+
+	s.EnablePoint(-4, 10)
+	s.EnablePoint(0, -1)
+	box, _ = s.GetBoundingBox()
+	assert.Equal(box, Rect{-4, -1, 2, 10}, "Invalid bounding box.")
+	assert.Equal(s.GetWidth(), 7, "Invalid width.")
+	assert.Equal(s.GetHeight(), 12, "Invalid height.")
+
+	// The bounding box shrinks.
+	s.DisablePoint(-4, 10)
+	box, _ = s.GetBoundingBox()
+	assert.Equal(box, Rect{0, -1, 2, 3}, "Invalid bounding box.")
+
+	// The bounding box is remade after disabling all the points.
+	s.DisablePoint(0, -1)
+	s.DisablePoint(2, 3)
+	s.EnablePoint(100, 100)
+	box, _ = s.GetBoundingBox()
+	assert.Equal(box, Rect{100, 100, 100, 100}, "Invalid bounding box.")
+}
+
+// Test the sparse universe has not edges.
+func TestSparseWrap(t *testing.T) {
+	assert := assert.New(t)
+	s := NewSparse()
+
+	x, y, inside := s.Wrap(-100, 100)
+	assert.Equal([2]int{x, y}, [2]int{-100, 100}, "The position changes.")
+	assert.Equal(inside, true, "The position is outside.")
+	assert.Equal(s.IsBounded(), false, "The sparse universe is bounded.")
+}
+
+// Test the function ForEachEnabled.
+func TestSparseForEachEnabled(t *testing.T) {
+	assert := assert.New(t)
+	s := NewSparse()
+	positions := map[[2]int]bool{{1, 1}: true, {-1, 2}: true, {3, -3}: true}
+
+	for p := range positions {
+		s.EnablePoint(p[0], p[1])
+	}
+
+	visited := map[[2]int]bool{}
+	s.ForEachEnabled(func(x, y int) {
+		visited[[2]int{x, y}] = true
+	})
+
+	assert.Equal(visited, positions, "Invalid positions visited.")
+}
@@ -0,0 +1,142 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// Topology of the matrix. It defines the shape of the cells and which points are adjacent.
+type Topology int
+
+const (
+	// Square cells: each point has 8 adjacents, the Moore neighbourhood.
+	TOPOLOGY_SQUARE Topology = iota
+
+	// Hexagonal cells in axial coordinates: the column is the axis `q` and the row is the
+	// axis `r`, so each row is shifted half cell to the right of the previous one and the
+	// matrix is drawn as a rhombus. Each point has 6 adjacents.
+	TOPOLOGY_HEXAGONAL
+
+	// Triangular cells: the triangles of the positions with `x`+`y` even point up and the
+	// others point down. Each point has 12 adjacents, the triangles that share an edge or a
+	// vertex with it.
+	TOPOLOGY_TRIANGULAR
+)
+
+// Names of the topologies.
+var topologyNames map[Topology]string = map[Topology]string{
+	TOPOLOGY_SQUARE:     "square",
+	TOPOLOGY_HEXAGONAL:  "hexagonal",
+	TOPOLOGY_TRIANGULAR: "triangular",
+}
+
+// Relative positions of the adjacents in the square topology.
+var squareOffsets [][2]int = [][2]int{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-1, 0}, {1, 0},
+	{-1, 1}, {0, 1}, {1, 1},
+}
+
+// Relative positions of the adjacents in the hexagonal topology.
+var hexagonalOffsets [][2]int = [][2]int{
+	{0, -1}, {1, -1},
+	{-1, 0}, {1, 0},
+	{-1, 1}, {0, 1},
+}
+
+// Relative positions of the adjacents of the triangles pointing up in the triangular topology.
+// The row above only touches the top vertex and the row below shares the base.
+var triangularUpOffsets [][2]int = [][2]int{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-2, 0}, {-1, 0}, {1, 0}, {2, 0},
+	{-2, 1}, {-1, 1}, {0, 1}, {1, 1}, {2, 1},
+}
+
+// Relative positions of the adjacents of the triangles pointing down in the triangular
+// topology. They are the adjacents of the triangles pointing up mirrored vertically.
+var triangularDownOffsets [][2]int = [][2]int{
+	{-2, -1}, {-1, -1}, {0, -1}, {1, -1}, {2, -1},
+	{-2, 0}, {-1, 0}, {1, 0}, {2, 0},
+	{-1, 1}, {0, 1}, {1, 1},
+}
+
+// Returns the topology with the name `name`.
+// Returns an error whether the name is unknown.
+func ParseTopology(name string) (Topology, error) {
+	for t, n := range topologyNames {
+		if n == name {
+			return t, nil
+		}
+	}
+
+	return TOPOLOGY_SQUARE, InvalidTopologyError(name)
+}
+
+// Checks if the topology is valid.
+func (self Topology) IsValid() bool {
+	_, ok := topologyNames[self]
+	return ok
+}
+
+// Returns the relative positions of the adjacents of the point `x`, `y`.
+// The slice is shared, so it must not be modified.
+func (self Topology) GetAdjacentOffsets(x, y int) [][2]int {
+	switch self {
+	case TOPOLOGY_HEXAGONAL:
+		return hexagonalOffsets
+	case TOPOLOGY_TRIANGULAR:
+		if IsTriangleUp(x, y) {
+			return triangularUpOffsets
+		}
+
+		return triangularDownOffsets
+	}
+
+	return squareOffsets
+}
+
+// Returns the number of adjacents of each point.
+func (self Topology) GetAdjacentsNum() int {
+	return len(self.GetAdjacentOffsets(0, 0))
+}
+
+// Returns the maximum distance, horizontal or vertical, of the adjacents to the point.
+func (self Topology) GetRadius() int {
+	if self == TOPOLOGY_TRIANGULAR {
+		return 2
+	}
+
+	return 1
+}
+
+// Checks if the topology can be used with the boundary mode `boundary` in a matrix of size
+// `width`x`height`. The Klein bottle mirrors the cells, so it only joins the square cells.
+// The triangular topology needs an even size in the joined edges to alternate the triangles.
+// Returns nil if all is correct or an error whether they can not be used together.
+func (self Topology) checkBoundary(boundary Boundary, width, height int) error {
+	if self == TOPOLOGY_SQUARE {
+		return nil
+	}
+
+	if boundary == BOUNDARY_KLEIN {
+		return TopologyError(self, fmt.Sprintf("it can not use the boundary mode \"%s\"", boundary))
+	}
+
+	if self == TOPOLOGY_TRIANGULAR && ((boundary.WrapsX() && width%2 != 0) || (boundary.WrapsY() && height%2 != 0)) {
+		return TopologyError(self, "the joined edges must have an even size")
+	}
+
+	return nil
+}
+
+func (self Topology) String() string {
+	if name, ok := topologyNames[self]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("topology(%d)", int(self))
+}
+
+// Checks if the triangle of the position `x`, `y` of the triangular topology points up.
+func IsTriangleUp(x, y int) bool {
+	return (x+y)%2 == 0
+}
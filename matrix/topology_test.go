@@ -0,0 +1,96 @@
+package matrix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function ParseTopology.
+func TestParseTopology(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, topology := range []Topology{TOPOLOGY_SQUARE, TOPOLOGY_HEXAGONAL, TOPOLOGY_TRIANGULAR} {
+		parsed, err := ParseTopology(topology.String())
+		assert.Equal(err, nil, "There is an error.")
+		assert.Equal(parsed, topology, fmt.Sprintf("Invalid topology %s.", topology))
+	}
+
+	_, err := ParseTopology("octagonal")
+	assert.Equal(err, InvalidTopologyError("octagonal"), "The error does not match.")
+}
+
+// Test the adjacents of the topologies: the adjacency is symmetric and the point is not
+// adjacent to itself.
+func TestGetAdjacentOffsets(t *testing.T) {
+	assert := assert.New(t)
+	adjacents := map[Topology]int{TOPOLOGY_SQUARE: 8, TOPOLOGY_HEXAGONAL: 6, TOPOLOGY_TRIANGULAR: 12}
+
+	for topology, n := range adjacents {
+		assert.Equal(topology.GetAdjacentsNum(), n, fmt.Sprintf("Invalid adjacents of the topology %s.", topology))
+
+		for _, p := range [][2]int{{0, 0}, {1, 0}, {0, 1}, {-3, 2}} {
+			for _, offset := range topology.GetAdjacentOffsets(p[0], p[1]) {
+				message := fmt.Sprintf("Invalid adjacent %v of the point %v in the topology %s.", offset, p, topology)
+				assert.NotEqual(offset, [2]int{0, 0}, message)
+
+				found := false
+				for _, back := range topology.GetAdjacentOffsets(p[0]+offset[0], p[1]+offset[1]) {
+					found = found || back == [2]int{-offset[0], -offset[1]}
+				}
+
+				assert.Equal(found, true, message)
+			}
+		}
+	}
+
+	// The triangles pointing up share the base with the triangle below.
+	assert.Equal(IsTriangleUp(0, 0), true, "The triangle 0x0 points down.")
+	assert.Equal(IsTriangleUp(0, 1), false, "The triangle 0x1 points up.")
+	assert.Equal(IsTriangleUp(-1, 0), false, "The triangle -1x0 points up.")
+	assert.Equal(TOPOLOGY_TRIANGULAR.GetRadius(), 2, "Invalid radius of the triangular topology.")
+}
+
+// Test the function NewWithTopology.
+func TestNewMatrixWithTopology(t *testing.T) {
+	assert := assert.New(t)
+
+	m, err := NewWithTopology(min, min, BOUNDARY_TORUS, TOPOLOGY_HEXAGONAL)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(m.GetTopology(), TOPOLOGY_HEXAGONAL, "Invalid topology.")
+	assert.Equal(m.String(), "Matrix (10x10, hexagonal)", "Invalid string.")
+
+	m, _ = New(min, min)
+	assert.Equal(m.GetTopology(), TOPOLOGY_SQUARE, "The default topology is not square.")
+
+	tests := []struct {
+		width, height int
+		boundary      Boundary
+		topology      Topology
+		err           error
+	}{
+		{min, min, BOUNDARY_DEAD, Topology(-1), InvalidTopologyError("topology(-1)")},
+		{min, min, BOUNDARY_KLEIN, TOPOLOGY_HEXAGONAL, TopologyError(TOPOLOGY_HEXAGONAL, "it can not use the boundary mode \"klein\"")},
+		{min, min, BOUNDARY_KLEIN, TOPOLOGY_TRIANGULAR, TopologyError(TOPOLOGY_TRIANGULAR, "it can not use the boundary mode \"klein\"")},
+		{min + 1, min, BOUNDARY_TORUS, TOPOLOGY_TRIANGULAR, TopologyError(TOPOLOGY_TRIANGULAR, "the joined edges must have an even size")},
+		{min, min + 1, BOUNDARY_CYLINDER_Y, TOPOLOGY_TRIANGULAR, TopologyError(TOPOLOGY_TRIANGULAR, "the joined edges must have an even size")},
+		{min + 1, min + 1, BOUNDARY_DEAD, TOPOLOGY_TRIANGULAR, nil},
+		{min, min + 1, BOUNDARY_CYLINDER_X, TOPOLOGY_TRIANGULAR, nil},
+		{min, min, BOUNDARY_KLEIN, TOPOLOGY_SQUARE, nil},
+	}
+
+	for _, test := range tests {
+		_, err := NewWithTopology(test.width, test.height, test.boundary, test.topology)
+		message := fmt.Sprintf("Invalid error of the topology %s with the boundary %s.", test.topology, test.boundary)
+		assert.Equal(err, test.err, message)
+	}
+
+	// Only the dense storage has other topologies.
+	_, err = NewUniverseWithTopology(STORAGE_PACKED, min, min, BOUNDARY_DEAD, TOPOLOGY_HEXAGONAL)
+	assert.Equal(err, TopologyError(TOPOLOGY_HEXAGONAL, "it needs the dense storage"), "The error does not match.")
+
+	u, err := NewUniverseWithTopology(STORAGE_SPARSE, min, min, BOUNDARY_DEAD, TOPOLOGY_SQUARE)
+	assert.Equal(err, nil, "There is an error with the square topology.")
+	assert.Equal(u.IsBounded(), false, "The universe is not sparse.")
+}
@@ -0,0 +1,180 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// Storage of the points used by the game.
+// Bounded universes, as `Matrix`, have a fixed size and the positions outside of it are invalid.
+// Unbounded universes, as `Sparse`, accept any position, even with negative coordinates.
+type Universe interface {
+	// Enable the point of the position `x`, `y`.
+	EnablePoint(x, y int) error
+
+	// Disable the point of the position `x`, `y`.
+	DisablePoint(x, y int) error
+
+	// Checks if the point of the position `x`, `y` is enabled.
+	IsEnabled(x, y int) (bool, error)
+
+	// Returns the value of the point of the position `x`, `y`.
+	GetPoint(x, y int) (int, error)
+
+	// Disable all the points.
+	Reset()
+
+	// Returns the width. In unbounded universes it is the width of the bounding box.
+	GetWidth() int
+
+	// Returns the height. In unbounded universes it is the height of the bounding box.
+	GetHeight() int
+
+	// Returns the width and height.
+	GetSize() (int, int)
+
+	// Get the points enabled.
+	GetPointsEnabled() int
+
+	// Returns the boundary mode.
+	GetBoundary() Boundary
+
+	// Maps the position `x`, `y` to a valid position using the boundary mode.
+	// The third value returned is false when the position falls beyond a dead edge.
+	Wrap(x, y int) (int, int, bool)
+
+	// Returns false whether the universe has not edges.
+	IsBounded() bool
+
+	// Returns the smallest rectangle with all the points enabled.
+	// The second value is false when there are not points enabled.
+	GetBoundingBox() (Rect, bool)
+
+	// Calls the function `callback` with the position of each point enabled.
+	ForEachEnabled(callback func(x, y int))
+}
+
+// Rectangle of positions. The positions `MinX`, `MinY` and `MaxX`, `MaxY` are included.
+type Rect struct {
+	MinX, MinY int
+	MaxX, MaxY int
+}
+
+// Returns the width and height of the rectangle.
+func (self Rect) GetSize() (int, int) {
+	return self.MaxX - self.MinX + 1, self.MaxY - self.MinY + 1
+}
+
+// Checks if the position `x`, `y` is inside of the rectangle.
+func (self Rect) Contains(x, y int) bool {
+	return x >= self.MinX && x <= self.MaxX && y >= self.MinY && y <= self.MaxY
+}
+
+// Returns the smallest rectangle with the rectangle and the position `x`, `y`.
+func (self Rect) Extend(x, y int) Rect {
+	if x < self.MinX {
+		self.MinX = x
+	}
+
+	if x > self.MaxX {
+		self.MaxX = x
+	}
+
+	if y < self.MinY {
+		self.MinY = y
+	}
+
+	if y > self.MaxY {
+		self.MaxY = y
+	}
+
+	return self
+}
+
+func (self Rect) String() string {
+	return fmt.Sprintf("(%d, %d)-(%d, %d)", self.MinX, self.MinY, self.MaxX, self.MaxY)
+}
+
+// Kind of storage used by a universe.
+type Storage int
+
+const (
+	// The storage chosen by the universe users. It is `STORAGE_PACKED` unless they need other.
+	STORAGE_DEFAULT Storage = iota
+
+	// `Packed`: a bit per point.
+	STORAGE_PACKED
+
+	// `Matrix`: a slice of slices with a value per point.
+	STORAGE_DENSE
+
+	// `Sparse`: a hash set with the points enabled.
+	STORAGE_SPARSE
+)
+
+// Names of the storages.
+var storageNames map[Storage]string = map[Storage]string{
+	STORAGE_DEFAULT: "default",
+	STORAGE_PACKED:  "packed",
+	STORAGE_DENSE:   "dense",
+	STORAGE_SPARSE:  "sparse",
+}
+
+// Returns the storage with the name `name`.
+// Returns an error whether the name is unknown.
+func ParseStorage(name string) (Storage, error) {
+	for s, n := range storageNames {
+		if n == name {
+			return s, nil
+		}
+	}
+
+	return STORAGE_DENSE, InvalidStorageError(name)
+}
+
+func (self Storage) String() string {
+	if name, ok := storageNames[self]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("storage(%d)", int(self))
+}
+
+// Makes a new universe using the storage `storage`.
+// `width`, `height` and `boundary` are ignored by the unbounded storages, although they only
+// accept the boundary mode `BOUNDARY_DEAD`.
+// It returns an error whether the storage, the size or the boundary mode are invalid.
+func NewUniverse(storage Storage, width, height int, boundary Boundary) (Universe, error) {
+	switch storage {
+	case STORAGE_DEFAULT, STORAGE_PACKED:
+		return NewPacked(width, height, boundary)
+	case STORAGE_DENSE:
+		return NewWithBoundary(width, height, boundary)
+	case STORAGE_SPARSE:
+		if boundary != BOUNDARY_DEAD {
+			return nil, InvalidBoundaryError(boundary.String())
+		}
+
+		return NewSparse(), nil
+	}
+
+	return nil, InvalidStorageError(storage.String())
+}
+
+// Same as `NewUniverse` but the universe uses the topology `topology`.
+// Only the storage `STORAGE_DENSE` has other topologies than `TOPOLOGY_SQUARE`.
+// It returns an error whether the topology can not be used with the storage or the boundary.
+func NewUniverseWithTopology(storage Storage, width, height int, boundary Boundary, topology Topology) (Universe, error) {
+	if topology == TOPOLOGY_SQUARE {
+		return NewUniverse(storage, width, height, boundary)
+	}
+
+	if !topology.IsValid() {
+		return nil, InvalidTopologyError(topology.String())
+	}
+
+	if storage != STORAGE_DENSE {
+		return nil, TopologyError(topology, "it needs the dense storage")
+	}
+
+	return NewWithTopology(width, height, boundary, topology)
+}
@@ -0,0 +1,64 @@
+package matrix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function NewUniverse.
+func TestNewUniverse(t *testing.T) {
+	assert := assert.New(t)
+
+	u, err := NewUniverse(STORAGE_DENSE, min, min, BOUNDARY_TORUS)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(u.IsBounded(), true, "The dense universe is unbounded.")
+	assert.Equal(u.GetBoundary(), BOUNDARY_TORUS, "Invalid boundary.")
+
+	u, err = NewUniverse(STORAGE_SPARSE, 0, 0, BOUNDARY_DEAD)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(u.IsBounded(), false, "The sparse universe is bounded.")
+
+	_, err = NewUniverse(STORAGE_DENSE, -1, -1, BOUNDARY_DEAD)
+	assert.Equal(err, InvalidSizeError(-1, -1), "The error does not match.")
+
+	_, err = NewUniverse(STORAGE_SPARSE, min, min, BOUNDARY_TORUS)
+	assert.Equal(err, InvalidBoundaryError("torus"), "The error does not match.")
+
+	u, err = NewUniverse(STORAGE_DEFAULT, min, min, BOUNDARY_DEAD)
+	assert.Equal(err, nil, "There is an error.")
+	_, packed := u.(*Packed)
+	assert.Equal(packed, true, "The default universe is not packed.")
+
+	_, err = NewUniverse(Storage(-1), min, min, BOUNDARY_DEAD)
+	assert.Equal(err, InvalidStorageError("storage(-1)"), "The error does not match.")
+}
+
+// Test the function ParseStorage.
+func TestParseStorage(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := ParseStorage("sparse")
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(s, STORAGE_SPARSE, "Invalid storage.")
+
+	_, err = ParseStorage("tape")
+	assert.Equal(err, InvalidStorageError("tape"), "The error does not match.")
+}
+
+// Test the bounding box of the dense matrix.
+func TestMatrixBoundingBox(t *testing.T) {
+	assert := assert.New(t)
+	m, _ := New(min, min)
+
+	_, ok := m.GetBoundingBox()
+	assert.Equal(ok, false, "There is a bounding box without points.")
+
+	m.EnablePoint(2, 7)
+	m.EnablePoint(5, 1)
+	box, ok := m.GetBoundingBox()
+	assert.Equal(ok, true, "There is not bounding box.")
+	assert.Equal(box, Rect{2, 1, 5, 7}, "Invalid bounding box.")
+	assert.Equal(box.Contains(3, 3), true, "The position is outside.")
+	assert.Equal(box.Contains(1, 3), false, "The position is inside.")
+}
@@ -0,0 +1,109 @@
+package pattern
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/davidnotplay/gameoflife/game"
+)
+
+// Name of the plaintext format (.cells files), used in the errors.
+const CELLS_FORMAT string = "Plaintext"
+
+// Reads a pattern in plaintext format from `r`.
+// The lines starting with '!' are comments, and the rest are rows of points: '.' is a point
+// disabled and 'O' is a point enabled. The rows can be shorter than the pattern width.
+// It returns an error, with the line and column, whether the pattern is malformed.
+func ReadCells(r io.Reader) (*Pattern, error) {
+	p := &Pattern{Positions: []game.Position{}}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+
+		if strings.HasPrefix(text, "!") {
+			comment := strings.TrimSpace(text[1:])
+
+			switch {
+			case strings.HasPrefix(comment, "Name:"):
+				p.Name = strings.TrimSpace(comment[len("Name:"):])
+			case strings.HasPrefix(comment, "Author:"):
+				p.Author = strings.TrimSpace(comment[len("Author:"):])
+			default:
+				p.Comments = append(p.Comments, comment)
+			}
+
+			continue
+		}
+
+		for i, c := range text {
+			switch c {
+			case '.':
+			case 'O', '*':
+				p.Positions = append(p.Positions, game.Position{i, p.Height})
+			default:
+				return nil, SyntaxError(CELLS_FORMAT, line, i+1, fmt.Sprintf("invalid character '%c'", c))
+			}
+		}
+
+		if len(text) > p.Width {
+			p.Width = len(text)
+		}
+
+		p.Height++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Returns the row `row` using the character `dead` for the points disabled and `alive` for
+// the points enabled. The dead points at the end of the row are omitted.
+func encodeCellsRow(row []bool, dead, alive byte) string {
+	last := len(row) - 1
+	for last >= 0 && !row[last] {
+		last--
+	}
+
+	b := make([]byte, last+1)
+	for x := range b {
+		b[x] = dead
+		if row[x] {
+			b[x] = alive
+		}
+	}
+
+	return string(b)
+}
+
+// Writes the pattern `p` in plaintext format in `w`.
+// The dead points at the end of the rows are omitted.
+func WriteCells(w io.Writer, p *Pattern) error {
+	bw := bufio.NewWriter(w)
+
+	if p.Name != "" {
+		fmt.Fprintf(bw, "!Name: %s\n", p.Name)
+	}
+
+	if p.Author != "" {
+		fmt.Fprintf(bw, "!Author: %s\n", p.Author)
+	}
+
+	for _, comment := range p.Comments {
+		fmt.Fprintf(bw, "!%s\n", comment)
+	}
+
+	for _, row := range p.getRows() {
+		bw.WriteString(encodeCellsRow(row, '.', 'O') + "\n")
+	}
+
+	return bw.Flush()
+}
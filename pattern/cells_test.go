@@ -0,0 +1,63 @@
+package pattern
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const gliderCells string = `!Name: Glider
+!Author: Richard K. Guy
+!The smallest, most common, and first discovered spaceship.
+.O
+..O
+OOO
+`
+
+// Test the function ReadCells.
+func TestReadCells(t *testing.T) {
+	assert := assert.New(t)
+	p, err := ReadCells(strings.NewReader(gliderCells))
+
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(p.Name, "Glider", "Invalid name.")
+	assert.Equal(p.Author, "Richard K. Guy", "Invalid author.")
+	assert.Equal(len(p.Comments), 1, "Invalid comments.")
+	assert.Equal([2]int{p.Width, p.Height}, [2]int{3, 3}, "Invalid size.")
+	assert.Equal(auxPositionsSet(p.Positions), auxPositionsSet(glider), "Invalid positions.")
+
+	// Empty rows.
+	p, err = ReadCells(strings.NewReader("O\r\n\r\n..O\r\n"))
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal([2]int{p.Width, p.Height}, [2]int{3, 3}, "Invalid size.")
+	assert.Equal(len(p.Positions), 2, "Invalid number of points.")
+}
+
+// Test the errors of the function ReadCells.
+func TestReadCellsError(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[string]error{
+		".O\n..x":   SyntaxError(CELLS_FORMAT, 2, 3, "invalid character 'x'"),
+		"!C\n.O o.": SyntaxError(CELLS_FORMAT, 2, 3, "invalid character ' '"),
+	}
+
+	for text, expected := range tests {
+		p, err := ReadCells(strings.NewReader(text))
+		assert.Equal(p, (*Pattern)(nil), "The pattern is not nil.")
+		assert.Equal(err, expected, fmt.Sprintf("The error of %q does not match.", text))
+	}
+}
+
+// Test the function WriteCells.
+func TestWriteCells(t *testing.T) {
+	assert := assert.New(t)
+	p, _ := ReadCells(strings.NewReader(gliderCells))
+
+	var b bytes.Buffer
+	err := WriteCells(&b, p)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(b.String(), gliderCells, "Invalid plaintext.")
+}
@@ -0,0 +1,77 @@
+package pattern
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Returns true when the line `text` is a row of the plaintext format.
+func isCellsRow(text string) bool {
+	return strings.Trim(text, ".O*") == ""
+}
+
+// Returns the format of the pattern file `data`: `RLE_FORMAT`, `CELLS_FORMAT`,
+// `LIFE106_FORMAT` or `MACROCELL_FORMAT`.
+// The format is detected using the first lines of the file that are not empty.
+// Returns an error whether the format is unknown.
+func DetectFormat(data []byte) (string, error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		text := strings.TrimSpace(line)
+
+		switch {
+		case text == "":
+			continue
+		case strings.HasPrefix(text, macrocellHeader):
+			return MACROCELL_FORMAT, nil
+		case strings.HasPrefix(text, life106Header):
+			return LIFE106_FORMAT, nil
+		case strings.HasPrefix(text, "!"):
+			return CELLS_FORMAT, nil
+		case strings.HasPrefix(text, "#"):
+			// Comment of the RLE format.
+			continue
+		case strings.HasPrefix(text, "x ") || strings.HasPrefix(text, "x="):
+			return RLE_FORMAT, nil
+		case isCellsRow(text):
+			return CELLS_FORMAT, nil
+		default:
+			return "", UnknownFormatError()
+		}
+	}
+
+	return "", UnknownFormatError()
+}
+
+// Reads a pattern from `r`. The format is detected using the file content.
+// The points of the macrocell patterns are loaded in the pattern, so `ReadMacrocell`
+// must be used with the huge patterns.
+// Returns an error whether the format is unknown or the pattern is malformed.
+func Read(r io.Reader) (*Pattern, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := DetectFormat(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case MACROCELL_FORMAT:
+		u, err := ReadMacrocell(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		return FromHashlife(u), nil
+	case LIFE106_FORMAT:
+		return ReadLife106(bytes.NewReader(data))
+	case CELLS_FORMAT:
+		return ReadCells(bytes.NewReader(data))
+	default:
+		return ReadRLE(bytes.NewReader(data))
+	}
+}
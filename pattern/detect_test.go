@@ -0,0 +1,47 @@
+package pattern
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function DetectFormat.
+func TestDetectFormat(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[string]string{
+		gliderRLE:                   RLE_FORMAT,
+		"x=3,y=3\nbob$2bo$3o!":      RLE_FORMAT,
+		gliderCells:                 CELLS_FORMAT,
+		"\n.O\n..O\nOOO\n":          CELLS_FORMAT,
+		"#Life 1.06\n0 0\n":         LIFE106_FORMAT,
+		"\r\n#Life 1.06\r\n0 0\r\n": LIFE106_FORMAT,
+	}
+
+	for text, expected := range tests {
+		format, err := DetectFormat([]byte(text))
+		assert.Equal(err, nil, fmt.Sprintf("There is an error with %q.", text))
+		assert.Equal(format, expected, fmt.Sprintf("Invalid format of %q.", text))
+	}
+
+	for _, text := range []string{"", "#C only comments\n", "Hello world"} {
+		_, err := DetectFormat([]byte(text))
+		assert.Equal(err, UnknownFormatError(), fmt.Sprintf("The format of %q is known.", text))
+	}
+}
+
+// Test the function Read.
+func TestRead(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, text := range []string{gliderRLE, gliderCells, "#Life 1.06\n0 -1\n1 0\n-1 1\n0 1\n1 1\n"} {
+		p, err := Read(strings.NewReader(text))
+		assert.Equal(err, nil, "There is an error.")
+		assert.Equal(auxPositionsSet(p.Positions), auxPositionsSet(glider), "Invalid positions.")
+	}
+
+	_, err := Read(strings.NewReader("Hello world"))
+	assert.Equal(err, UnknownFormatError(), "The format is known.")
+}
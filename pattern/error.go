@@ -0,0 +1,31 @@
+package pattern
+
+import (
+	"fmt"
+)
+
+type syntaxError struct {
+	format  string
+	line    int
+	column  int
+	message string
+}
+
+func (e *syntaxError) Error() string {
+	message := "Invalid %s pattern. Line %d, column %d: %s."
+	return fmt.Sprintf(message, e.format, e.line, e.column, e.message)
+}
+
+func SyntaxError(format string, line, column int, message string) error {
+	return &syntaxError{format, line, column, message}
+}
+
+type unknownFormatError struct{}
+
+func (e *unknownFormatError) Error() string {
+	return "The pattern format is unknown."
+}
+
+func UnknownFormatError() error {
+	return &unknownFormatError{}
+}
@@ -0,0 +1,114 @@
+package pattern
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/davidnotplay/gameoflife/game"
+)
+
+// Name of the Life 1.06 format, used in the errors.
+const LIFE106_FORMAT string = "Life 1.06"
+
+// First line of the Life 1.06 files.
+const life106Header string = "#Life 1.06"
+
+// Reads a pattern in Life 1.06 format from `r`.
+// Every line has the coordinates "x y" of a point enabled. The coordinates can be negative,
+// so the points are moved to the top-left corner of the pattern.
+// It returns an error, with the line and column, whether the pattern is malformed.
+func ReadLife106(r io.Reader) (*Pattern, error) {
+	p := &Pattern{Positions: []game.Position{}}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	set := map[game.Position]bool{}
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+
+		if line == 1 {
+			if strings.TrimSpace(text) != life106Header {
+				return nil, SyntaxError(LIFE106_FORMAT, line, 1, fmt.Sprintf("the header \"%s\" is missing", life106Header))
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(text, "#") {
+			// Extension of some programs: "#N" name and "#D" description.
+			if strings.HasPrefix(text, "#N") {
+				p.Name = strings.TrimSpace(text[2:])
+			} else if strings.HasPrefix(text, "#D") {
+				p.Comments = append(p.Comments, strings.TrimSpace(text[2:]))
+			}
+
+			continue
+		}
+
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			column := len(text) - len(strings.TrimLeft(text, " \t")) + 1
+			return nil, SyntaxError(LIFE106_FORMAT, line, column, fmt.Sprintf("invalid coordinates \"%s\"", strings.TrimSpace(text)))
+		}
+
+		var pos game.Position
+		offset := 0
+		for i, field := range fields {
+			start := strings.Index(text[offset:], field) + offset
+			offset = start + len(field)
+
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, SyntaxError(LIFE106_FORMAT, line, start+1, fmt.Sprintf("invalid coordinate \"%s\"", field))
+			}
+
+			pos[i] = n
+		}
+
+		if !set[pos] {
+			set[pos] = true
+			p.Positions = append(p.Positions, pos)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if line == 0 {
+		return nil, SyntaxError(LIFE106_FORMAT, 1, 1, fmt.Sprintf("the header \"%s\" is missing", life106Header))
+	}
+
+	p.normalize()
+	return p, nil
+}
+
+// Writes the pattern `p` in Life 1.06 format in `w`.
+// The name and the comments are written with the "#N" and "#D" lines.
+func WriteLife106(w io.Writer, p *Pattern) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(life106Header + "\n")
+	if p.Name != "" {
+		fmt.Fprintf(bw, "#N %s\n", p.Name)
+	}
+
+	for _, comment := range p.Comments {
+		fmt.Fprintf(bw, "#D %s\n", comment)
+	}
+
+	for _, pos := range p.Positions {
+		fmt.Fprintf(bw, "%d %d\n", pos[0], pos[1])
+	}
+
+	return bw.Flush()
+}
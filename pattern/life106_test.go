@@ -0,0 +1,58 @@
+package pattern
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the function ReadLife106.
+func TestReadLife106(t *testing.T) {
+	assert := assert.New(t)
+	text := "#Life 1.06\n#N Glider\n#D A spaceship.\n0 -1\n1 0\n-1 1\n0 1\n1 1\n1 1\n"
+	p, err := ReadLife106(strings.NewReader(text))
+
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(p.Name, "Glider", "Invalid name.")
+	assert.Equal(p.Comments, []string{"A spaceship."}, "Invalid comments.")
+	assert.Equal([2]int{p.Width, p.Height}, [2]int{3, 3}, "Invalid size.")
+	assert.Equal(auxPositionsSet(p.Positions), auxPositionsSet(glider), "Invalid positions.")
+	assert.Equal(len(p.Positions), len(glider), "The repeated points are not removed.")
+}
+
+// Test the errors of the function ReadLife106.
+func TestReadLife106Error(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[string]error{
+		"":                     SyntaxError(LIFE106_FORMAT, 1, 1, "the header \"#Life 1.06\" is missing"),
+		"0 0\n":                SyntaxError(LIFE106_FORMAT, 1, 1, "the header \"#Life 1.06\" is missing"),
+		"#Life 1.06\n0 0 0":    SyntaxError(LIFE106_FORMAT, 2, 1, "invalid coordinates \"0 0 0\""),
+		"#Life 1.06\n  5":      SyntaxError(LIFE106_FORMAT, 2, 3, "invalid coordinates \"5\""),
+		"#Life 1.06\n1 1\n1 a": SyntaxError(LIFE106_FORMAT, 3, 3, "invalid coordinate \"a\""),
+	}
+
+	for text, expected := range tests {
+		p, err := ReadLife106(strings.NewReader(text))
+		assert.Equal(p, (*Pattern)(nil), "The pattern is not nil.")
+		assert.Equal(err, expected, fmt.Sprintf("The error of %q does not match.", text))
+	}
+}
+
+// Test the function WriteLife106.
+func TestWriteLife106(t *testing.T) {
+	assert := assert.New(t)
+	p := &Pattern{Name: "Glider", Width: 3, Height: 3, Positions: []game.Position{{1, 0}, {2, 1}}}
+
+	var b bytes.Buffer
+	err := WriteLife106(&b, p)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(b.String(), "#Life 1.06\n#N Glider\n1 0\n2 1\n", "Invalid Life 1.06.")
+
+	read, err := ReadLife106(&b)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(auxPositionsSet(read.Positions), auxPositionsSet([]game.Position{{0, 0}, {1, 1}}), "Invalid positions.")
+}
@@ -0,0 +1,325 @@
+package pattern
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/hashlife"
+)
+
+// Name of the macrocell format (.mc files), used in the errors.
+const MACROCELL_FORMAT string = "Macrocell"
+
+// First characters of the macrocell files.
+const macrocellHeader string = "[M2]"
+
+// Level of the nodes written as 8x8 leaves.
+const macrocellLeafLevel uint = 3
+
+// Maximum level of the nodes. The positions of the points must fit in an int.
+const macrocellMaxLevel uint = 62
+
+// Size of the leaves.
+const macrocellLeafSize int = 1 << macrocellLeafLevel
+
+// State of the macrocell reader.
+type macrocellReader struct {
+	universe *hashlife.Universe
+
+	// Rulestring of the pattern. Empty when the file has not rule.
+	rule string
+
+	// Nodes read. The index 0 is the empty node.
+	nodes []*hashlife.Node
+}
+
+// Makes the universe, with the rule of the file, the first time it is called.
+func (self *macrocellReader) getUniverse(line int) (*hashlife.Universe, error) {
+	if self.universe != nil {
+		return self.universe, nil
+	}
+
+	var rule *game.Rule
+	var err error
+
+	if self.rule != "" {
+		if rule, err = game.ParseRule(self.rule); err != nil {
+			return nil, SyntaxError(MACROCELL_FORMAT, line, 1, strings.TrimSuffix(err.Error(), "."))
+		}
+	}
+
+	if self.universe, err = hashlife.New(rule); err != nil {
+		return nil, SyntaxError(MACROCELL_FORMAT, line, 1, strings.TrimSuffix(err.Error(), "."))
+	}
+
+	return self.universe, nil
+}
+
+// Returns the node of level `level` with the points `points`, whose corner is in `x`, `y`.
+func makeLeafNode(u *hashlife.Universe, points *[macrocellLeafSize][macrocellLeafSize]bool, x, y int, level uint) *hashlife.Node {
+	if level == 0 {
+		return hashlife.Leaf(points[y][x])
+	}
+
+	half := 1 << (level - 1)
+	return u.JoinNodes(
+		makeLeafNode(u, points, x, y, level-1),
+		makeLeafNode(u, points, x+half, y, level-1),
+		makeLeafNode(u, points, x, y+half, level-1),
+		makeLeafNode(u, points, x+half, y+half, level-1))
+}
+
+// Parses the leaf line `text`, the line number `line` of the file. Example: ".*$..*$***$".
+func (self *macrocellReader) parseLeaf(text string, line int) error {
+	var points [macrocellLeafSize][macrocellLeafSize]bool
+	x, y := 0, 0
+
+	for i, c := range text {
+		switch c {
+		case '.', '*':
+			if x >= macrocellLeafSize || y >= macrocellLeafSize {
+				message := fmt.Sprintf("the leaf is bigger than %dx%d", macrocellLeafSize, macrocellLeafSize)
+				return SyntaxError(MACROCELL_FORMAT, line, i+1, message)
+			}
+
+			points[y][x] = c == '*'
+			x++
+		case '$':
+			x, y = 0, y+1
+		default:
+			return SyntaxError(MACROCELL_FORMAT, line, i+1, fmt.Sprintf("invalid character '%c'", c))
+		}
+	}
+
+	u, err := self.getUniverse(line)
+	if err != nil {
+		return err
+	}
+
+	self.nodes = append(self.nodes, makeLeafNode(u, &points, 0, 0, macrocellLeafLevel))
+	return nil
+}
+
+// Parses the node line `text`, the line number `line` of the file.
+// Example: "4 1 2 0 3", the level of the node and the indexes of its four quadrants.
+func (self *macrocellReader) parseNode(text string, line int) error {
+	fields := strings.Fields(text)
+	if len(fields) != 5 {
+		return SyntaxError(MACROCELL_FORMAT, line, 1, fmt.Sprintf("invalid node \"%s\"", text))
+	}
+
+	var values [5]int
+	offset := 0
+	for i, field := range fields {
+		start := strings.Index(text[offset:], field) + offset
+		offset = start + len(field)
+
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return SyntaxError(MACROCELL_FORMAT, line, start+1, fmt.Sprintf("invalid number \"%s\"", field))
+		}
+
+		if i == 0 && (uint(n) <= macrocellLeafLevel || uint(n) > macrocellMaxLevel) {
+			return SyntaxError(MACROCELL_FORMAT, line, start+1, fmt.Sprintf("invalid level %d", n))
+		}
+
+		if i > 0 && n >= len(self.nodes) {
+			return SyntaxError(MACROCELL_FORMAT, line, start+1, fmt.Sprintf("the node %d is not defined", n))
+		}
+
+		if i > 0 && n > 0 && self.nodes[n].GetLevel() != uint(values[0]-1) {
+			message := fmt.Sprintf("the level of the node %d is not %d", n, values[0]-1)
+			return SyntaxError(MACROCELL_FORMAT, line, start+1, message)
+		}
+
+		values[i] = n
+	}
+
+	u, err := self.getUniverse(line)
+	if err != nil {
+		return err
+	}
+
+	var quadrants [4]*hashlife.Node
+	for i, index := range values[1:] {
+		quadrants[i] = self.nodes[index]
+		if index == 0 {
+			quadrants[i] = u.GetEmptyNode(uint(values[0] - 1))
+		}
+	}
+
+	self.nodes = append(self.nodes, u.JoinNodes(quadrants[0], quadrants[1], quadrants[2], quadrants[3]))
+	return nil
+}
+
+// Reads a pattern in macrocell format from `r`. The macrocell files store the quadtree of the
+// pattern, so the pattern is returned in a HashLife universe, that can store billions of points.
+// The last node of the file is the root node and it is centered in the position 0, 0.
+// It returns an error, with the line and column, whether the pattern is malformed.
+func ReadMacrocell(r io.Reader) (*hashlife.Universe, error) {
+	reader := &macrocellReader{nodes: []*hashlife.Node{nil}}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	var generation uint64
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+
+		if line == 1 {
+			if !strings.HasPrefix(text, macrocellHeader) {
+				return nil, SyntaxError(MACROCELL_FORMAT, line, 1, fmt.Sprintf("the header \"%s\" is missing", macrocellHeader))
+			}
+
+			continue
+		}
+
+		var err error
+		switch {
+		case text == "":
+		case strings.HasPrefix(text, "#R"):
+			// Remove the topology suffix used by some programs. Example: "B3/S23:T20,20".
+			reader.rule = strings.SplitN(strings.TrimSpace(text[2:]), ":", 2)[0]
+		case strings.HasPrefix(text, "#G"):
+			value := strings.TrimSpace(text[2:])
+			if generation, err = strconv.ParseUint(value, 10, 64); err != nil {
+				err = SyntaxError(MACROCELL_FORMAT, line, 3, fmt.Sprintf("invalid generation \"%s\"", value))
+			}
+		case strings.HasPrefix(text, "#"):
+			// Comments.
+		case text[0] >= '0' && text[0] <= '9':
+			err = reader.parseNode(text, line)
+		default:
+			err = reader.parseLeaf(text, line)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if line == 0 {
+		return nil, SyntaxError(MACROCELL_FORMAT, 1, 1, fmt.Sprintf("the header \"%s\" is missing", macrocellHeader))
+	}
+
+	u, err := reader.getUniverse(line)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reader.nodes) > 1 {
+		u.SetRoot(reader.nodes[len(reader.nodes)-1])
+	}
+
+	u.SetGeneration(generation)
+	return u, nil
+}
+
+// Writes the 8x8 points of the node `n`, whose corner is in `x`, `y`, in `points`.
+func getLeafPoints(n *hashlife.Node, points *[macrocellLeafSize][macrocellLeafSize]bool, x, y int) {
+	if n.GetPopulation() == 0 {
+		return
+	}
+
+	if n.GetLevel() == 0 {
+		points[y][x] = true
+		return
+	}
+
+	half := 1 << (n.GetLevel() - 1)
+	nw, ne, sw, se := n.GetQuadrants()
+	getLeafPoints(nw, points, x, y)
+	getLeafPoints(ne, points, x+half, y)
+	getLeafPoints(sw, points, x, y+half)
+	getLeafPoints(se, points, x+half, y+half)
+}
+
+// Returns the leaf line of the node `n` of level 3. The dead points at the end of the rows
+// and the empty rows at the end are omitted.
+func encodeMacrocellLeaf(n *hashlife.Node) string {
+	var points [macrocellLeafSize][macrocellLeafSize]bool
+	var b strings.Builder
+
+	getLeafPoints(n, &points, 0, 0)
+
+	last := macrocellLeafSize - 1
+	for last >= 0 && points[last] == [macrocellLeafSize]bool{} {
+		last--
+	}
+
+	for y := 0; y <= last; y++ {
+		row := encodeCellsRow(points[y][:], '.', '*')
+		b.WriteString(row)
+		b.WriteByte('$')
+	}
+
+	return b.String()
+}
+
+// Writes the universe `u` in macrocell format in `w`.
+// The nodes are written once, so the size of the file depends on the nodes of the quadtree,
+// not on the number of points.
+func WriteMacrocell(w io.Writer, u *hashlife.Universe) error {
+	bw := bufio.NewWriter(w)
+	indexes := map[*hashlife.Node]int{}
+
+	fmt.Fprintf(bw, "%s (gameoflife)\n", macrocellHeader)
+	fmt.Fprintf(bw, "#R %s\n", u.GetRule())
+	if u.GetGeneration() > 0 {
+		fmt.Fprintf(bw, "#G %d\n", u.GetGeneration())
+	}
+
+	// Writes the node `n` after its quadrants and returns its index.
+	var write func(n *hashlife.Node) int
+	write = func(n *hashlife.Node) int {
+		// The population of the huge nodes can overflow, so the empty nodes are compared.
+		if n == u.GetEmptyNode(n.GetLevel()) {
+			return 0
+		}
+
+		if index, ok := indexes[n]; ok {
+			return index
+		}
+
+		if n.GetLevel() == macrocellLeafLevel {
+			bw.WriteString(encodeMacrocellLeaf(n) + "\n")
+		} else {
+			nw, ne, sw, se := n.GetQuadrants()
+			children := [4]int{write(nw), write(ne), write(sw), write(se)}
+			fmt.Fprintf(bw, "%d %d %d %d %d\n", n.GetLevel(), children[0], children[1], children[2], children[3])
+		}
+
+		indexes[n] = len(indexes) + 1
+		return indexes[n]
+	}
+
+	write(u.GetRoot())
+	return bw.Flush()
+}
+
+// Makes a new pattern with the points enabled and the rule of the HashLife universe `u`.
+// The pattern size is the bounding box of the points.
+func FromHashlife(u *hashlife.Universe) *Pattern {
+	p := &Pattern{Rule: u.GetRule().String(), Positions: []game.Position{}}
+	box, ok := u.GetBoundingBox()
+
+	if !ok {
+		return p
+	}
+
+	p.Width, p.Height = box.GetSize()
+	u.ForEachEnabledIn(box, func(x, y int) {
+		p.Positions = append(p.Positions, game.Position{x - box.MinX, y - box.MinY})
+	})
+
+	return p
+}
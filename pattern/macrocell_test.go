@@ -0,0 +1,155 @@
+package pattern
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/hashlife"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+const gliderMacrocell string = `[M2] (golly 2.0)
+#R B3/S23
+#G 12
+.*$..*$***$
+4 0 0 0 1
+`
+
+// Returns a macrocell file with a node of level `level` full of blocks, separated 8 points.
+func auxBlocksMacrocell(level int) string {
+	text := "[M2]\n**$**$\n"
+	for l := 4; l <= level; l++ {
+		i := l - 3
+		text += fmt.Sprintf("%d %d %d %d %d\n", l, i, i, i, i)
+	}
+
+	return text
+}
+
+// Test the function ReadMacrocell.
+func TestReadMacrocell(t *testing.T) {
+	assert := assert.New(t)
+	u, err := ReadMacrocell(strings.NewReader(gliderMacrocell))
+
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(u.GetRule().String(), "B3/S23", "Invalid rule.")
+	assert.Equal(u.GetGeneration(), uint64(12), "Invalid generation.")
+	assert.Equal(u.GetRoot().GetLevel(), uint(4), "Invalid level.")
+	assert.Equal(u.GetPointsEnabled(), len(glider), "Invalid points enabled.")
+
+	// The root node is centered in the position 0, 0.
+	for _, pos := range glider {
+		assert.Equal(u.IsEnabled(pos[0], pos[1]), true, fmt.Sprintf("The point %dx%d is disabled.", pos[0], pos[1]))
+	}
+
+	// Empty file.
+	u, err = ReadMacrocell(strings.NewReader("[M2]\n"))
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(u.GetPointsEnabled(), 0, "Invalid points enabled.")
+}
+
+// Test the function ReadMacrocell with a pattern of 2^56 points.
+func TestReadMacrocellHuge(t *testing.T) {
+	assert := assert.New(t)
+	u, err := ReadMacrocell(strings.NewReader(auxBlocksMacrocell(30)))
+
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(u.GetPointsEnabled(), 1<<56, "Invalid points enabled.")
+
+	// The blocks are still lifes.
+	u.Advance(1 << 20)
+	assert.Equal(u.GetPointsEnabled(), 1<<56, "Invalid points enabled after the cycles.")
+
+	// Export a window.
+	m, _ := matrix.New(10, 10)
+	err = u.ExportTo(m, 0, 0)
+	assert.Equal(err, nil, "There is an error exporting the window.")
+	assert.Equal(m.GetPointsEnabled(), 16, "Invalid points enabled in the window.")
+
+	for _, pos := range []game.Position{{0, 0}, {1, 1}, {8, 0}, {9, 9}} {
+		enabled, _ := m.IsEnabled(pos[0], pos[1])
+		assert.Equal(enabled, true, fmt.Sprintf("The point %dx%d is disabled.", pos[0], pos[1]))
+	}
+}
+
+// Test the errors of the function ReadMacrocell.
+func TestReadMacrocellError(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[string]error{
+		"":                               SyntaxError(MACROCELL_FORMAT, 1, 1, "the header \"[M2]\" is missing"),
+		"x = 1, y = 1\no!":               SyntaxError(MACROCELL_FORMAT, 1, 1, "the header \"[M2]\" is missing"),
+		"[M2]\n.*$.o$":                   SyntaxError(MACROCELL_FORMAT, 2, 5, "invalid character 'o'"),
+		"[M2]\n.........*$":              SyntaxError(MACROCELL_FORMAT, 2, 9, "the leaf is bigger than 8x8"),
+		"[M2]\n$$$$$$$$*$":               SyntaxError(MACROCELL_FORMAT, 2, 9, "the leaf is bigger than 8x8"),
+		"[M2]\n*$\n4 0 1 0":              SyntaxError(MACROCELL_FORMAT, 3, 1, "invalid node \"4 0 1 0\""),
+		"[M2]\n*$\n4 0 2 0 0":            SyntaxError(MACROCELL_FORMAT, 3, 5, "the node 2 is not defined"),
+		"[M2]\n*$\n4 1 1 1 1\n4 1 0 0 2": SyntaxError(MACROCELL_FORMAT, 4, 9, "the level of the node 2 is not 3"),
+		"[M2]\n*$\n3 1 1 1 1":            SyntaxError(MACROCELL_FORMAT, 3, 1, "invalid level 3"),
+		"[M2]\n*$\n4 1 -1 1 1":           SyntaxError(MACROCELL_FORMAT, 3, 5, "invalid number \"-1\""),
+		"[M2]\n#G x\n*$":                 SyntaxError(MACROCELL_FORMAT, 2, 3, "invalid generation \"x\""),
+		"[M2]\n#R B3/S2x\n*$":            SyntaxError(MACROCELL_FORMAT, 3, 1, "The rule \"B3/S2x\" is invalid: invalid character 'x'"),
+		"[M2]\n#R B03/S23\n*$":           SyntaxError(MACROCELL_FORMAT, 3, 1, "The rule \"B03/S23\" is invalid: the B0 rules need a bounded storage"),
+	}
+
+	for text, expected := range tests {
+		u, err := ReadMacrocell(strings.NewReader(text))
+		assert.Equal(u, (*hashlife.Universe)(nil), "The universe is not nil.")
+		assert.Equal(err, expected, fmt.Sprintf("The error of %q does not match.", text))
+	}
+}
+
+// Test the function WriteMacrocell.
+func TestWriteMacrocell(t *testing.T) {
+	assert := assert.New(t)
+	u, _ := ReadMacrocell(strings.NewReader(gliderMacrocell))
+
+	var b bytes.Buffer
+	err := WriteMacrocell(&b, u)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(b.String(), "[M2] (gameoflife)\n#R B3/S23\n#G 12\n.*$..*$***$\n4 0 0 0 1\n", "Invalid macrocell.")
+
+	// The nodes are written once.
+	u, _ = ReadMacrocell(strings.NewReader(auxBlocksMacrocell(40)))
+	b.Reset()
+	WriteMacrocell(&b, u)
+	assert.Equal(b.String(), "[M2] (gameoflife)\n#R B3/S23\n"+auxBlocksMacrocell(40)[len("[M2]\n"):], "Invalid macrocell of blocks.")
+}
+
+// Test the points are the same after writing and reading a universe.
+func TestWriteMacrocellReadMacrocell(t *testing.T) {
+	assert := assert.New(t)
+	u, _ := hashlife.New(game.MustParseRule("B36/S23"))
+	points := []game.Position{{0, 0}, {1, 0}, {2, 0}, {-1000, 7}, {123456789, -987654321}}
+	for _, p := range points {
+		u.EnablePoint(p[0], p[1])
+	}
+
+	u.Advance(5)
+
+	var b bytes.Buffer
+	WriteMacrocell(&b, u)
+	read, err := ReadMacrocell(&b)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(read.GetRule().String(), "B36/S23", "Invalid rule.")
+	assert.Equal(read.GetGeneration(), uint64(5), "Invalid generation.")
+
+	expected, found := []game.Position{}, []game.Position{}
+	u.ForEachEnabled(func(x, y int) { expected = append(expected, game.Position{x, y}) })
+	read.ForEachEnabled(func(x, y int) { found = append(found, game.Position{x, y}) })
+	assert.Equal(auxPositionsSet(found), auxPositionsSet(expected), "Invalid positions.")
+}
+
+// Test the function Read with a macrocell file.
+func TestReadMacrocellPattern(t *testing.T) {
+	assert := assert.New(t)
+	p, err := Read(strings.NewReader(gliderMacrocell))
+
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(p.Rule, "B3/S23", "Invalid rule.")
+	assert.Equal([2]int{p.Width, p.Height}, [2]int{3, 3}, "Invalid size.")
+	assert.Equal(auxPositionsSet(p.Positions), auxPositionsSet(glider), "Invalid positions.")
+}
@@ -0,0 +1,161 @@
+package pattern
+
+import (
+	"sort"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Pattern read from, or written to, a file.
+type Pattern struct {
+	// Name of the pattern.
+	Name string
+
+	// Author of the pattern.
+	Author string
+
+	// Comment lines.
+	Comments []string
+
+	// Rulestring of the pattern. Empty when the file has not rule.
+	Rule string
+
+	// Size of the pattern.
+	Width, Height int
+
+	// Positions of the points enabled, relative to the pattern top-left corner.
+	Positions []game.Position
+}
+
+// Makes a new pattern with the points enabled of the universe `m`.
+// The pattern size is the bounding box of the points and the points are sorted by rows.
+func FromUniverse(m matrix.Universe) *Pattern {
+	p := &Pattern{Positions: []game.Position{}}
+	box, ok := m.GetBoundingBox()
+
+	if !ok {
+		return p
+	}
+
+	p.Width, p.Height = box.GetSize()
+	m.ForEachEnabled(func(x, y int) {
+		p.Positions = append(p.Positions, game.Position{x - box.MinX, y - box.MinY})
+	})
+
+	// The sparse universes have not order.
+	sort.Slice(p.Positions, func(i, j int) bool {
+		a, b := p.Positions[i], p.Positions[j]
+		return a[1] < b[1] || (a[1] == b[1] && a[0] < b[0])
+	})
+
+	return p
+}
+
+// Makes a new pattern with the points enabled and the rule of the game `g`.
+func FromGame(g *game.Game) *Pattern {
+	p := FromUniverse(g.GetMatrix())
+	p.Rule = g.GetRule().String()
+	return p
+}
+
+// Returns the rule of the pattern. The Conway's rule is returned when the pattern has not rule.
+func (self *Pattern) GetRule() (*game.Rule, error) {
+	if self.Rule == "" {
+		return game.ParseRule(game.CONWAY_RULE)
+	}
+
+	return game.ParseRule(self.Rule)
+}
+
+// Makes a new game of size `width`x`height` with the pattern points.
+// In the bounded storages the pattern is placed in the center of the matrix.
+// The pattern rule is used when `options` has not rule.
+func (self *Pattern) NewGame(width, height int, options game.Options) (*game.Game, error) {
+	var err error
+
+	if options.Rule == nil {
+		if options.Rule, err = self.GetRule(); err != nil {
+			return nil, err
+		}
+	}
+
+	dx, dy := 0, 0
+	if options.Storage != matrix.STORAGE_SPARSE {
+		dx, dy = (width-self.Width)/2, (height-self.Height)/2
+	}
+
+	positions := make([]game.Position, len(self.Positions))
+	for i, p := range self.Positions {
+		positions[i] = game.Position{p[0] + dx, p[1] + dy}
+	}
+
+	return game.NewWithOptions(width, height, positions, options)
+}
+
+// Returns the pattern points in rows: `rows[y][x]` is true when the point is enabled.
+func (self *Pattern) getRows() [][]bool {
+	rows := make([][]bool, self.Height)
+	for y := range rows {
+		rows[y] = make([]bool, self.Width)
+	}
+
+	for _, p := range self.Positions {
+		if p[0] >= 0 && p[1] >= 0 && p[0] < self.Width && p[1] < self.Height {
+			rows[p[1]][p[0]] = true
+		}
+	}
+
+	return rows
+}
+
+// Moves the points to the top-left corner and updates the pattern size.
+// Used by the formats with coordinates that can be negative.
+func (self *Pattern) normalize() {
+	if len(self.Positions) == 0 {
+		self.Width, self.Height = 0, 0
+		return
+	}
+
+	box := matrix.Rect{
+		MinX: self.Positions[0][0], MinY: self.Positions[0][1],
+		MaxX: self.Positions[0][0], MaxY: self.Positions[0][1],
+	}
+
+	for _, p := range self.Positions {
+		box = box.Extend(p[0], p[1])
+	}
+
+	for i, p := range self.Positions {
+		self.Positions[i] = game.Position{p[0] - box.MinX, p[1] - box.MinY}
+	}
+
+	self.Width, self.Height = box.GetSize()
+}
+
+// Enables the pattern points in the universe `m`, with the pattern top-left corner in `x`x`y`.
+// Returns an error whether a point is outside of the universe.
+func (self *Pattern) ExportTo(m matrix.Universe, x, y int) error {
+	for _, p := range self.Positions {
+		if err := m.EnablePoint(p[0]+x, p[1]+y); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Makes a new matrix of size `width`x`height`, with the boundary `boundary`,
+// and the pattern points in its top-left corner.
+func (self *Pattern) NewMatrix(width, height int, boundary matrix.Boundary) (*matrix.Matrix, error) {
+	m, err := matrix.NewWithBoundary(width, height, boundary)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := self.ExportTo(m, 0, 0); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
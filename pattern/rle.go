@@ -0,0 +1,347 @@
+package pattern
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/davidnotplay/gameoflife/game"
+)
+
+// Name of the Run Length Encoded format, used in the errors.
+const RLE_FORMAT string = "RLE"
+
+// Maximum length of the lines written in the RLE files.
+const rleLineLength int = 70
+
+// State of the RLE body reader.
+type rleReader struct {
+	pattern *Pattern
+
+	// Current position in the pattern.
+	x, y int
+
+	// Run count read and its position in the file. Zero when there is not run count.
+	count        int
+	countLine    int
+	countColumn  int
+	finished     bool
+	headerParsed bool
+}
+
+// Parses the comment line `text` and stores it in the pattern.
+func (self *rleReader) parseComment(text string) {
+	kind, value := "", ""
+	if len(text) > 1 {
+		kind, value = text[1:2], strings.TrimSpace(text[2:])
+	}
+
+	switch kind {
+	case "N":
+		self.pattern.Name = value
+	case "O":
+		self.pattern.Author = value
+	case "P", "R":
+		// Position of the pattern. The positions are relative to the top-left corner.
+	default:
+		self.pattern.Comments = append(self.pattern.Comments, value)
+	}
+}
+
+// Parses the header line `text`, the line number `line` of the file.
+// Example: "x = 3, y = 3, rule = B3/S23". The field "rule" must be the last one.
+func (self *rleReader) parseHeader(text string, line int) error {
+	found := map[string]bool{}
+	column := 1
+
+	// Returns the number of spaces at the beginning of `s`.
+	spaces := func(s string) int {
+		return len(s) - len(strings.TrimLeft(s, " \t"))
+	}
+
+	fields := strings.Split(text, ",")
+	for i := 0; i < len(fields); i++ {
+		// The rule is the last field and it has the rest of the line, because the rulestrings
+		// can have commas. Example: "R5,C0,M1,S34..58,B34..45,NM".
+		field := fields[i]
+		if strings.TrimSpace(strings.SplitN(field, "=", 2)[0]) == "rule" {
+			field = strings.Join(fields[i:], ",")
+			i = len(fields)
+		}
+
+		// Columns of the field and its value, without the spaces.
+		fieldColumn := column + spaces(field)
+		start := column
+		column += len(field) + 1
+
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return SyntaxError(RLE_FORMAT, line, fieldColumn, fmt.Sprintf("invalid header field \"%s\"", strings.TrimSpace(field)))
+		}
+
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		valueColumn := start + len(parts[0]) + 1 + spaces(parts[1])
+
+		switch key {
+		case "x", "y":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return SyntaxError(RLE_FORMAT, line, valueColumn, fmt.Sprintf("invalid size \"%s\"", value))
+			}
+
+			if key == "x" {
+				self.pattern.Width = n
+			} else {
+				self.pattern.Height = n
+			}
+		case "rule":
+			// Remove the topology suffix used by some programs. Example: "B3/S23:T20,20".
+			rule := strings.SplitN(value, ":", 2)[0]
+			if _, err := game.ParseRule(rule); err != nil {
+				return SyntaxError(RLE_FORMAT, line, valueColumn, strings.TrimSuffix(err.Error(), "."))
+			}
+
+			self.pattern.Rule = rule
+		default:
+			return SyntaxError(RLE_FORMAT, line, fieldColumn, fmt.Sprintf("unknown header field \"%s\"", key))
+		}
+
+		found[key] = true
+	}
+
+	for _, key := range []string{"x", "y"} {
+		if !found[key] {
+			return SyntaxError(RLE_FORMAT, line, 1, fmt.Sprintf("the header has not the field \"%s\"", key))
+		}
+	}
+
+	self.headerParsed = true
+	return nil
+}
+
+// Returns the run count read and clears it. It is 1 when there is not run count.
+func (self *rleReader) popCount() int {
+	n := self.count
+	self.count = 0
+
+	if n == 0 {
+		return 1
+	}
+
+	return n
+}
+
+// Returns the error of the tags that go beyond the header size, in the line `line` and
+// the column `column`.
+func (self *rleReader) sizeError(line, column int) error {
+	message := fmt.Sprintf("the pattern is bigger than the header size (%dx%d)", self.pattern.Width, self.pattern.Height)
+	return SyntaxError(RLE_FORMAT, line, column, message)
+}
+
+// Parses the body line `text`, the line number `line` of the file.
+func (self *rleReader) parseBody(text string, line int) error {
+	// The run counts can not be bigger than the header size, so they do not overflow.
+	limit := self.pattern.Width
+	if self.pattern.Height > limit {
+		limit = self.pattern.Height
+	}
+
+	for i, c := range text {
+		column := i + 1
+
+		switch {
+		case c >= '0' && c <= '9':
+			if self.count == 0 {
+				if c == '0' {
+					return SyntaxError(RLE_FORMAT, line, column, "the run count can not be zero")
+				}
+
+				self.countLine, self.countColumn = line, column
+			}
+
+			digit := int(c - '0')
+			if digit > limit || self.count > (limit-digit)/10 {
+				message := fmt.Sprintf("the run count is bigger than the header size (%dx%d)", self.pattern.Width, self.pattern.Height)
+				return SyntaxError(RLE_FORMAT, self.countLine, self.countColumn, message)
+			}
+
+			self.count = self.count*10 + digit
+		case c == ' ' || c == '\t':
+		case c == 'b' || c == '.':
+			n := self.popCount()
+			if self.x+n > self.pattern.Width {
+				return self.sizeError(line, column)
+			}
+
+			self.x += n
+		case c == 'o':
+			n := self.popCount()
+			if self.x+n > self.pattern.Width || self.y >= self.pattern.Height {
+				return self.sizeError(line, column)
+			}
+
+			for j := 0; j < n; j++ {
+				self.pattern.Positions = append(self.pattern.Positions, game.Position{self.x + j, self.y})
+			}
+
+			self.x += n
+		case c == '$':
+			// The last row can end with '$', so the position can be just below the pattern.
+			n := self.popCount()
+			if self.y+n > self.pattern.Height {
+				return self.sizeError(line, column)
+			}
+
+			self.y += n
+			self.x = 0
+		case c == '!':
+			if self.count != 0 {
+				return SyntaxError(RLE_FORMAT, self.countLine, self.countColumn, "the run count has not tag")
+			}
+
+			self.finished = true
+			return nil
+		default:
+			return SyntaxError(RLE_FORMAT, line, column, fmt.Sprintf("invalid character '%c'", c))
+		}
+	}
+
+	return nil
+}
+
+// Reads a pattern in Run Length Encoded format from `r`.
+// It returns an error, with the line and column, whether the pattern is malformed.
+func ReadRLE(r io.Reader) (*Pattern, error) {
+	reader := &rleReader{pattern: &Pattern{Positions: []game.Position{}}}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+
+	for scanner.Scan() && !reader.finished {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(text)
+
+		if strings.HasPrefix(trimmed, "#") {
+			reader.parseComment(trimmed)
+			continue
+		}
+
+		var err error
+		if !reader.headerParsed {
+			if trimmed == "" {
+				continue
+			}
+
+			err = reader.parseHeader(text, line)
+		} else {
+			err = reader.parseBody(text, line)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !reader.headerParsed {
+		return nil, SyntaxError(RLE_FORMAT, line+1, 1, "unexpected end of file, the header is missing")
+	}
+
+	if !reader.finished {
+		return nil, SyntaxError(RLE_FORMAT, line+1, 1, "unexpected end of file, the terminator '!' is missing")
+	}
+
+	return reader.pattern, nil
+}
+
+// Returns the run `n` of the tag `tag` in RLE format.
+func formatRLERun(n int, tag byte) string {
+	if n == 1 {
+		return string(tag)
+	}
+
+	return strconv.Itoa(n) + string(tag)
+}
+
+// Returns the runs of the points of the row `row`. The dead points at the end are omitted.
+func encodeRLERow(row []bool) []string {
+	runs := []string{}
+
+	for x := 0; x < len(row); {
+		n := 1
+		for x+n < len(row) && row[x+n] == row[x] {
+			n++
+		}
+
+		if row[x] {
+			runs = append(runs, formatRLERun(n, 'o'))
+		} else if x+n < len(row) {
+			runs = append(runs, formatRLERun(n, 'b'))
+		}
+
+		x += n
+	}
+
+	return runs
+}
+
+// Writes the pattern `p` in Run Length Encoded format in `w`.
+func WriteRLE(w io.Writer, p *Pattern) error {
+	bw := bufio.NewWriter(w)
+
+	if p.Name != "" {
+		fmt.Fprintf(bw, "#N %s\n", p.Name)
+	}
+
+	if p.Author != "" {
+		fmt.Fprintf(bw, "#O %s\n", p.Author)
+	}
+
+	for _, comment := range p.Comments {
+		fmt.Fprintf(bw, "#C %s\n", comment)
+	}
+
+	fmt.Fprintf(bw, "x = %d, y = %d", p.Width, p.Height)
+	if p.Rule != "" {
+		fmt.Fprintf(bw, ", rule = %s", p.Rule)
+	}
+	bw.WriteString("\n")
+
+	// Runs of all the rows.
+	runs, lastY := []string{}, 0
+	for y, row := range p.getRows() {
+		rowRuns := encodeRLERow(row)
+		if len(rowRuns) == 0 {
+			continue
+		}
+
+		if y > lastY {
+			runs = append(runs, formatRLERun(y-lastY, '$'))
+		}
+
+		runs, lastY = append(runs, rowRuns...), y
+	}
+
+	runs = append(runs, "!")
+
+	// Write the runs without exceeding the line length.
+	length := 0
+	for _, run := range runs {
+		if length+len(run) > rleLineLength {
+			bw.WriteString("\n")
+			length = 0
+		}
+
+		bw.WriteString(run)
+		length += len(run)
+	}
+
+	bw.WriteString("\n")
+	return bw.Flush()
+}
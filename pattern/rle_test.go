@@ -0,0 +1,207 @@
+package pattern
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+const gliderRLE string = `#N Glider
+#O Richard K. Guy
+#C The smallest, most common, and first discovered spaceship.
+#C www.conwaylife.com/wiki/index.php?title=Glider
+x = 3, y = 3, rule = B3/S23
+bob$2bo$3o!
+`
+
+var glider []game.Position = []game.Position{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+
+// Returns the positions as a set.
+func auxPositionsSet(positions []game.Position) map[game.Position]bool {
+	set := map[game.Position]bool{}
+	for _, p := range positions {
+		set[p] = true
+	}
+
+	return set
+}
+
+// Test the function ReadRLE.
+func TestReadRLE(t *testing.T) {
+	assert := assert.New(t)
+	p, err := ReadRLE(strings.NewReader(gliderRLE))
+
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(p.Name, "Glider", "Invalid name.")
+	assert.Equal(p.Author, "Richard K. Guy", "Invalid author.")
+	assert.Equal(len(p.Comments), 2, "Invalid comments.")
+	assert.Equal(p.Rule, "B3/S23", "Invalid rule.")
+	assert.Equal([2]int{p.Width, p.Height}, [2]int{3, 3}, "Invalid size.")
+	assert.Equal(auxPositionsSet(p.Positions), auxPositionsSet(glider), "Invalid positions.")
+}
+
+// Test the function ReadRLE with runs in several lines and without rule.
+func TestReadRLEMultiline(t *testing.T) {
+	assert := assert.New(t)
+	// Gosper glider gun.
+	gun := "x=36,y=9\r\n" +
+		"24bo$22bobo$12b2o6b2o12b2o$11bo3bo4b2o12b2o$2o8bo5bo3b2o$2o8bo3bob2o4b\r\n" +
+		"obo$10bo5bo7bo$11bo3bo$12b2o!  Text after the terminator.\r\n"
+
+	p, err := ReadRLE(strings.NewReader(gun))
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(p.Rule, "", "Invalid rule.")
+	assert.Equal(len(p.Positions), 36, "Invalid number of points.")
+
+	set := auxPositionsSet(p.Positions)
+	for _, pos := range []game.Position{{24, 0}, {22, 1}, {24, 1}, {22, 5}, {24, 5}, {24, 6}, {12, 8}, {13, 8}} {
+		assert.Equal(set[pos], true, fmt.Sprintf("The point %dx%d is disabled.", pos[0], pos[1]))
+	}
+
+	// The run count is in the previous line.
+	p, err = ReadRLE(strings.NewReader("x = 5, y = 1\n2\nb3\no!"))
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(auxPositionsSet(p.Positions), auxPositionsSet([]game.Position{{2, 0}, {3, 0}, {4, 0}}), "Invalid positions.")
+}
+
+// Test the errors of the function ReadRLE.
+func TestReadRLEError(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[string]error{
+		"":                                 SyntaxError(RLE_FORMAT, 1, 1, "unexpected end of file, the header is missing"),
+		"#C comment\n":                     SyntaxError(RLE_FORMAT, 2, 1, "unexpected end of file, the header is missing"),
+		"x = 3, y = 3\nbo$2bo":             SyntaxError(RLE_FORMAT, 3, 1, "unexpected end of file, the terminator '!' is missing"),
+		"x = 3\nbo!":                       SyntaxError(RLE_FORMAT, 1, 1, "the header has not the field \"y\""),
+		"x = 3, y = a\nbo!":                SyntaxError(RLE_FORMAT, 1, 12, "invalid size \"a\""),
+		"x = 3, y = 3, z = 1\nbo!":         SyntaxError(RLE_FORMAT, 1, 15, "unknown header field \"z\""),
+		"x = 3, y\nbo!":                    SyntaxError(RLE_FORMAT, 1, 8, "invalid header field \"y\""),
+		"x = 3, y = 3, rule = B9/S23\nbo!": SyntaxError(RLE_FORMAT, 1, 22, "The rule \"B9/S23\" is invalid: invalid character '9'"),
+		"x = 3, y = 3\nbo$\n2bx!":          SyntaxError(RLE_FORMAT, 3, 3, "invalid character 'x'"),
+		"x = 3, y = 3\nbo$\n2b2o!":         SyntaxError(RLE_FORMAT, 3, 4, "the pattern is bigger than the header size (3x3)"),
+		"x = 3, y = 1\nbo$o!":              SyntaxError(RLE_FORMAT, 2, 4, "the pattern is bigger than the header size (3x1)"),
+		"x = 3, y = 3\n0bo!":               SyntaxError(RLE_FORMAT, 2, 1, "the run count can not be zero"),
+		"x = 3, y = 3\nbo$\n 2!":           SyntaxError(RLE_FORMAT, 3, 2, "the run count has not tag"),
+		"x = 3, y = 3\nbo$\n 99999999999999999999999o!": SyntaxError(RLE_FORMAT, 3, 2, "the run count is bigger than the header size (3x3)"),
+		"x = 3, y = 5\n\n6o!":                           SyntaxError(RLE_FORMAT, 3, 1, "the run count is bigger than the header size (3x5)"),
+		"x = 3, y = 3\n2b2bo!":                          SyntaxError(RLE_FORMAT, 2, 4, "the pattern is bigger than the header size (3x3)"),
+		"x = 3, y = 3\no3.!":                            SyntaxError(RLE_FORMAT, 2, 3, "the pattern is bigger than the header size (3x3)"),
+		"x = 3, y = 3\no2$2$o!":                         SyntaxError(RLE_FORMAT, 2, 5, "the pattern is bigger than the header size (3x3)"),
+	}
+
+	for text, expected := range tests {
+		p, err := ReadRLE(strings.NewReader(text))
+		assert.Equal(p, (*Pattern)(nil), "The pattern is not nil.")
+		assert.Equal(err, expected, fmt.Sprintf("The error of %q does not match.", text))
+	}
+}
+
+// Test the function WriteRLE.
+func TestWriteRLE(t *testing.T) {
+	assert := assert.New(t)
+	p := &Pattern{
+		Name:      "Glider",
+		Comments:  []string{"A comment."},
+		Rule:      "B3/S23",
+		Width:     3,
+		Height:    5,
+		Positions: []game.Position{{1, 0}, {2, 1}, {0, 4}, {1, 4}, {2, 4}},
+	}
+
+	var b bytes.Buffer
+	err := WriteRLE(&b, p)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(b.String(), "#N Glider\n#C A comment.\nx = 3, y = 5, rule = B3/S23\nbo$2bo3$3o!\n", "Invalid RLE.")
+}
+
+// Test the lines written by WriteRLE are short and the pattern is the same after reading it.
+func TestWriteRLEReadRLE(t *testing.T) {
+	assert := assert.New(t)
+	p := &Pattern{Rule: "B36/S23", Width: 200, Height: 50, Positions: []game.Position{}}
+	for x := 0; x < 200; x++ {
+		for y := 0; y < 50; y++ {
+			if (x*7+y*13)%5 < 2 {
+				p.Positions = append(p.Positions, game.Position{x, y})
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	WriteRLE(&b, p)
+	for _, line := range strings.Split(b.String(), "\n") {
+		assert.Equal(len(line) <= 70, true, fmt.Sprintf("The line %q is too long.", line))
+	}
+
+	read, err := ReadRLE(&b)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(read.Rule, p.Rule, "Invalid rule.")
+	assert.Equal([2]int{read.Width, read.Height}, [2]int{p.Width, p.Height}, "Invalid size.")
+	assert.Equal(auxPositionsSet(read.Positions), auxPositionsSet(p.Positions), "Invalid positions.")
+}
+
+// Test the rules with commas are the same after writing and reading them.
+func TestWriteRLEReadRLERules(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, rule := range []string{"R5,C0,M1,S34..58,B34..45,NM", "B3,10/S2"} {
+		p := &Pattern{Rule: rule, Width: 3, Height: 1, Positions: []game.Position{{0, 0}, {2, 0}}}
+
+		var b bytes.Buffer
+		WriteRLE(&b, p)
+		read, err := ReadRLE(&b)
+		assert.Equal(err, nil, fmt.Sprintf("There is an error reading the rule %s.", rule))
+		assert.Equal(read.Rule, rule, "Invalid rule.")
+		assert.Equal(auxPositionsSet(read.Positions), auxPositionsSet(p.Positions), "Invalid positions.")
+	}
+
+	// The topology suffix is removed after the rule with commas.
+	read, err := ReadRLE(strings.NewReader("x = 3, y = 1, rule = B3,10/S2:T20,20\nobo!"))
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(read.Rule, "B3,10/S2", "Invalid rule with the topology suffix.")
+}
+
+// Test the pattern of a game keeps the rule and the pattern makes the same game.
+func TestPatternGame(t *testing.T) {
+	assert := assert.New(t)
+	p, _ := ReadRLE(strings.NewReader("x = 3, y = 3, rule = B36/S23\nbob$2bo$3o!"))
+
+	g, err := p.NewGame(matrix.MINIMUM_SIZE+1, matrix.MINIMUM_SIZE+1, game.Options{})
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(g.GetRule().String(), "B36/S23", "Invalid rule.")
+
+	// The pattern is in the center.
+	enabled, _ := g.GetMatrix().IsEnabled(5, 4)
+	assert.Equal(enabled, true, "The point 5x4 is disabled.")
+	assert.Equal(g.GetMatrix().GetPointsEnabled(), len(glider), "Invalid points enabled.")
+
+	exported := FromGame(g)
+	assert.Equal(exported.Rule, "B36/S23", "Invalid rule.")
+	assert.Equal([2]int{exported.Width, exported.Height}, [2]int{3, 3}, "Invalid size.")
+	assert.Equal(auxPositionsSet(exported.Positions), auxPositionsSet(glider), "Invalid positions.")
+
+	// The rule of the options has priority.
+	g, _ = p.NewGame(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, game.Options{Rule: game.MustParseRule("B3/S23")})
+	assert.Equal(g.GetRule().String(), "B3/S23", "Invalid rule.")
+}
+
+// Test the function NewMatrix.
+func TestPatternNewMatrix(t *testing.T) {
+	assert := assert.New(t)
+	p, _ := ReadRLE(strings.NewReader(gliderRLE))
+
+	m, err := p.NewMatrix(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, matrix.BOUNDARY_DEAD)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(m.GetPointsEnabled(), len(glider), "Invalid points enabled.")
+	for _, pos := range glider {
+		enabled, _ := m.IsEnabled(pos[0], pos[1])
+		assert.Equal(enabled, true, fmt.Sprintf("The point %dx%d is disabled.", pos[0], pos[1]))
+	}
+
+	big := &Pattern{Width: 1, Height: 1, Positions: []game.Position{{matrix.MINIMUM_SIZE, 0}}}
+	_, err = big.NewMatrix(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE, matrix.BOUNDARY_DEAD)
+	assert.NotEqual(err, nil, "There is not error.")
+}
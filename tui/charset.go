@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Characters used to draw the points in the terminal.
+type Charset int
+
+const (
+	// Each character draws 1x2 points with the Unicode half blocks.
+	CHARSET_HALF_BLOCK Charset = iota
+
+	// Each character draws 2x4 points with the Unicode Braille patterns.
+	CHARSET_BRAILLE
+)
+
+var charsetNames map[Charset]string = map[Charset]string{
+	CHARSET_HALF_BLOCK: "half",
+	CHARSET_BRAILLE:    "braille",
+}
+
+// Half blocks indexed by the upper point (bit 0) and the lower point (bit 1).
+var halfBlocks [4]rune = [4]rune{' ', '▀', '▄', '█'}
+
+// First Braille pattern, without dots.
+const brailleBase rune = 0x2800
+
+// Bit of the Braille dot of each point of the character, indexed by `y` and `x`.
+var brailleDots [4][2]rune = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// Returns the charset of the name `name`: "half" or "braille".
+// Returns an error whether the name is invalid.
+func ParseCharset(name string) (Charset, error) {
+	for c, n := range charsetNames {
+		if n == name {
+			return c, nil
+		}
+	}
+
+	return 0, InvalidCharsetError(name)
+}
+
+func (self Charset) String() string {
+	if name, ok := charsetNames[self]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("charset(%d)", int(self))
+}
+
+// Returns the number of points, horizontally and vertically, drawn by each character.
+func (self Charset) GetCellSize() (int, int) {
+	if self == CHARSET_BRAILLE {
+		return 2, 4
+	}
+
+	return 1, 2
+}
+
+// Returns the character of the points of the universe `m` whose top-left corner is `x`, `y`.
+// The points outside of the rectangle `rect` are disabled.
+func (self Charset) getChar(m matrix.Universe, rect matrix.Rect, x, y int) rune {
+	isEnabled := func(px, py int) bool {
+		if !rect.Contains(px, py) {
+			return false
+		}
+
+		enabled, err := m.IsEnabled(px, py)
+		return err == nil && enabled
+	}
+
+	if self == CHARSET_BRAILLE {
+		r := brailleBase
+		for dy, row := range brailleDots {
+			for dx, dot := range row {
+				if isEnabled(x+dx, y+dy) {
+					r |= dot
+				}
+			}
+		}
+
+		return r
+	}
+
+	i := 0
+	if isEnabled(x, y) {
+		i |= 1
+	}
+
+	if isEnabled(x, y+1) {
+		i |= 2
+	}
+
+	return halfBlocks[i]
+}
+
+// Returns the lines of characters that draw the points of the universe `m` inside of the
+// rectangle `rect`.
+func (self Charset) Render(m matrix.Universe, rect matrix.Rect) []string {
+	cw, ch := self.GetCellSize()
+	lines := []string{}
+
+	for y := rect.MinY; y <= rect.MaxY; y += ch {
+		var b strings.Builder
+		for x := rect.MinX; x <= rect.MaxX; x += cw {
+			b.WriteRune(self.getChar(m, rect, x, y))
+		}
+
+		lines = append(lines, b.String())
+	}
+
+	return lines
+}
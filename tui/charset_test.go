@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a sparse universe with the points `positions` enabled.
+func auxUniverse(positions ...game.Position) matrix.Universe {
+	m := matrix.NewSparse()
+	for _, p := range positions {
+		m.EnablePoint(p[0], p[1])
+	}
+
+	return m
+}
+
+// Test the functions ParseCharset and String.
+func TestParseCharset(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, c := range []Charset{CHARSET_HALF_BLOCK, CHARSET_BRAILLE} {
+		parsed, err := ParseCharset(c.String())
+		assert.Equal(err, nil, "There is an error.")
+		assert.Equal(parsed, c, fmt.Sprintf("Invalid charset %s.", c))
+	}
+
+	_, err := ParseCharset("ascii")
+	assert.Equal(err, InvalidCharsetError("ascii"), "Invalid error.")
+	assert.Equal(err.Error(), "The charset \"ascii\" is invalid.", "Invalid error message.")
+	assert.Equal(Charset(9).String(), "charset(9)", "Invalid unknown charset name.")
+}
+
+// Test the function Render with the half blocks.
+func TestRenderHalfBlock(t *testing.T) {
+	assert := assert.New(t)
+	// Glider.
+	m := auxUniverse(game.Position{1, 0}, game.Position{2, 1}, game.Position{0, 2}, game.Position{1, 2}, game.Position{2, 2})
+
+	lines := CHARSET_HALF_BLOCK.Render(m, matrix.Rect{MaxX: 3, MaxY: 3})
+	assert.Equal(lines, []string{" ▀▄ ", "▀▀▀ "}, "Invalid lines.")
+
+	// The points outside of the rectangle are not drawn.
+	lines = CHARSET_HALF_BLOCK.Render(m, matrix.Rect{MinX: 1, MinY: 1, MaxX: 2, MaxY: 1})
+	assert.Equal(lines, []string{" ▀"}, "Invalid lines of the rectangle.")
+}
+
+// Test the function Render with the Braille patterns.
+func TestRenderBraille(t *testing.T) {
+	assert := assert.New(t)
+	m := auxUniverse(game.Position{0, 0}, game.Position{1, 3}, game.Position{2, 0}, game.Position{3, 0}, game.Position{0, 4})
+
+	lines := CHARSET_BRAILLE.Render(m, matrix.Rect{MaxX: 3, MaxY: 4})
+	assert.Equal(lines, []string{"⢁⠉", "⠁⠀"}, "Invalid lines.")
+
+	// Dense matrix.
+	dense, _ := matrix.New(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE)
+	dense.EnablePoint(9, 9)
+	lines = CHARSET_BRAILLE.Render(dense, matrix.Rect{MaxX: 9, MaxY: 9})
+	assert.Equal(len(lines), 3, "Invalid number of lines.")
+	assert.Equal([]rune(lines[2])[4], rune(0x2800|0x10), "Invalid last character.")
+}
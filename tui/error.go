@@ -0,0 +1,17 @@
+package tui
+
+import (
+	"fmt"
+)
+
+type invalidCharsetError string
+
+func (self *invalidCharsetError) Error() string {
+	message := "The charset \"%s\" is invalid."
+	return fmt.Sprintf(message, string(*self))
+}
+
+func InvalidCharsetError(name string) error {
+	err := invalidCharsetError(name)
+	return &err
+}
@@ -0,0 +1,245 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+)
+
+// Default time between two game cycles.
+const DEFAULT_DELAY time.Duration = 200 * time.Millisecond
+
+// Minimum and maximum time between two game cycles.
+const (
+	minDelay time.Duration = time.Millisecond
+	maxDelay time.Duration = 10 * time.Second
+)
+
+// Escape sequences of the terminal.
+const (
+	escHome       string = "\x1b[H"
+	escClear      string = "\x1b[2J"
+	escClearLine  string = "\x1b[K"
+	escHideCursor string = "\x1b[?25l"
+	escShowCursor string = "\x1b[?25h"
+)
+
+// Keys of the terminal.
+const (
+	KEY_PLAY   byte = ' '
+	KEY_STEP   byte = 'n'
+	KEY_FASTER byte = '+'
+	KEY_SLOWER byte = '-'
+	KEY_QUIT   byte = 'q'
+
+	// Ctrl+C, when the terminal is in raw mode.
+	keyInterrupt byte = 3
+)
+
+// Front end that draws the game in a terminal.
+type Terminal struct {
+	game *game.Game
+
+	// Where the game is drawn.
+	out io.Writer
+
+	// Characters used to draw the points.
+	charset Charset
+
+	// Points drawn.
+	viewport matrix.Rect
+
+	// Time between two game cycles.
+	delay time.Duration
+
+	playing bool
+}
+
+// Makes a new terminal front end for the game `g` that draws in `out` using the charset `charset`.
+// The bounded matrices are drawn entirely and the unbounded ones from the position 0, 0 to
+// the bounding box of its points.
+func New(g *game.Game, out io.Writer, charset Charset) *Terminal {
+	m := g.GetMatrix()
+	viewport := matrix.Rect{MaxX: m.GetWidth() - 1, MaxY: m.GetHeight() - 1}
+
+	if !m.IsBounded() {
+		if box, ok := m.GetBoundingBox(); ok {
+			viewport = viewport.Extend(box.MinX, box.MinY).Extend(box.MaxX, box.MaxY)
+		}
+	}
+
+	return &Terminal{g, out, charset, viewport, DEFAULT_DELAY, true}
+}
+
+// Sets the rectangle with the points drawn.
+func (self *Terminal) SetViewport(rect matrix.Rect) {
+	self.viewport = rect
+}
+
+// Returns the rectangle with the points drawn.
+func (self *Terminal) GetViewport() matrix.Rect {
+	return self.viewport
+}
+
+// Sets the time between two game cycles.
+func (self *Terminal) SetDelay(delay time.Duration) {
+	if delay < minDelay {
+		delay = minDelay
+	} else if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	self.delay = delay
+}
+
+// Returns the time between two game cycles.
+func (self *Terminal) GetDelay() time.Duration {
+	return self.delay
+}
+
+// Starts or stops the game cycles.
+func (self *Terminal) SetPlaying(playing bool) {
+	self.playing = playing
+}
+
+func (self *Terminal) IsPlaying() bool {
+	return self.playing
+}
+
+// Returns the game information: size, points enabled and cycles.
+func GetGameInfo(g *game.Game) string {
+	m := g.GetMatrix()
+	w, h, p := m.GetWidth(), m.GetHeight(), m.GetPointsEnabled()
+	return fmt.Sprintf("Size: %dx%d. Cells enabled %d. Cycles: %d.", w, h, p, g.GetCyclesNum())
+}
+
+// Returns the status line, with the game information and the keys.
+func (self *Terminal) getStatus() string {
+	state := "Playing"
+	if !self.playing {
+		state = "Paused"
+	}
+
+	keys := "[space] play/pause [n] step [+/-] speed [q] quit"
+	return fmt.Sprintf("%s %s (%s) %s", GetGameInfo(self.game), state, self.delay, keys)
+}
+
+// Draws the game and the status line.
+func (self *Terminal) Draw() error {
+	w := bufio.NewWriter(self.out)
+	w.WriteString(escHome)
+
+	for _, line := range self.charset.Render(self.game.GetMatrix(), self.viewport) {
+		w.WriteString(line + escClearLine + "\r\n")
+	}
+
+	w.WriteString(self.getStatus() + escClearLine)
+	return w.Flush()
+}
+
+// Runs a game cycle and draws it.
+func (self *Terminal) Step() error {
+	if err := self.game.Cycle(); err != nil {
+		return err
+	}
+
+	return self.Draw()
+}
+
+// Handles the key `key`. Returns true when the key quits the front end.
+func (self *Terminal) HandleKey(key byte) (bool, error) {
+	switch key {
+	case KEY_QUIT, keyInterrupt:
+		return true, nil
+	case KEY_PLAY:
+		self.playing = !self.playing
+	case KEY_STEP:
+		// The key steps only when the game is paused.
+		if !self.playing {
+			return false, self.Step()
+		}
+	case KEY_FASTER:
+		self.SetDelay(self.delay / 2)
+	case KEY_SLOWER:
+		self.SetDelay(self.delay * 2)
+	default:
+		return false, nil
+	}
+
+	return false, self.Draw()
+}
+
+// Reads the keys of `in` and sends them to `keys`, until the end of `in` or until `done`
+// is closed. The channel `keys` is closed at the end.
+func readKeys(in io.Reader, keys chan<- byte, done <-chan struct{}) {
+	defer close(keys)
+	buf := make([]byte, 1)
+
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			select {
+			case keys <- buf[0]:
+			case <-done:
+				return
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Draws the game and runs the cycles, while the game is playing, until the key quit is
+// read from `in` or the end of `in` is reached. The terminal must be in raw mode to read
+// the keys without waiting the end of line.
+func (self *Terminal) Run(in io.Reader) error {
+	keys, done := make(chan byte), make(chan struct{})
+	defer close(done)
+	go readKeys(in, keys, done)
+
+	io.WriteString(self.out, escHideCursor+escClear)
+	defer io.WriteString(self.out, escShowCursor+"\r\n")
+
+	if err := self.Draw(); err != nil {
+		return err
+	}
+
+	delay := self.delay
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+
+	for {
+		// The keys can change the speed.
+		if delay != self.delay {
+			delay = self.delay
+			ticker.Reset(delay)
+		}
+
+		var tick <-chan time.Time
+		if self.playing {
+			tick = ticker.C
+		}
+
+		select {
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+
+			quit, err := self.HandleKey(key)
+			if quit || err != nil {
+				return err
+			}
+		case <-tick:
+			if err := self.Step(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davidnotplay/gameoflife/game"
+	"github.com/davidnotplay/gameoflife/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a new terminal with a blinker in a dense game of 10x10.
+func auxTerminal() (*Terminal, *bytes.Buffer) {
+	var out bytes.Buffer
+	g, _ := game.NewWithOptions(matrix.MINIMUM_SIZE, matrix.MINIMUM_SIZE,
+		[]game.Position{{4, 5}, {5, 5}, {6, 5}}, game.Options{Storage: matrix.STORAGE_DENSE})
+
+	return New(g, &out, CHARSET_HALF_BLOCK), &out
+}
+
+// Test the function GetGameInfo.
+func TestGetGameInfo(t *testing.T) {
+	assert := assert.New(t)
+	term, _ := auxTerminal()
+
+	assert.Equal(GetGameInfo(term.game), "Size: 10x10. Cells enabled 3. Cycles: 0.", "Invalid information.")
+	term.game.Cycle()
+	assert.Equal(GetGameInfo(term.game), "Size: 10x10. Cells enabled 3. Cycles: 1.", "Invalid information after a cycle.")
+}
+
+// Test the function Draw.
+func TestDraw(t *testing.T) {
+	assert := assert.New(t)
+	term, out := auxTerminal()
+
+	assert.Equal(term.GetViewport(), matrix.Rect{MaxX: 9, MaxY: 9}, "Invalid viewport.")
+	assert.Equal(term.Draw(), nil, "There is an error.")
+
+	lines := strings.Split(out.String(), "\r\n")
+	assert.Equal(len(lines), 6, "Invalid number of lines.")
+	assert.Equal(lines[0], escHome+"          "+escClearLine, "Invalid first line.")
+	assert.Equal(lines[2], "    ▄▄▄   "+escClearLine, "Invalid line of the blinker.")
+	assert.Equal(strings.HasPrefix(lines[5], "Size: 10x10. Cells enabled 3. Cycles: 0. Playing"), true, "Invalid status line.")
+}
+
+// Test the function HandleKey.
+func TestHandleKey(t *testing.T) {
+	assert := assert.New(t)
+	term, out := auxTerminal()
+
+	term.HandleKey(KEY_PLAY)
+	assert.Equal(term.IsPlaying(), false, "The game is playing.")
+	assert.Equal(strings.Contains(out.String(), "Paused"), true, "The status is not paused.")
+
+	term.HandleKey(KEY_STEP)
+	assert.Equal(term.game.GetCyclesNum(), uint(1), "The game has not stepped.")
+
+	term.HandleKey(KEY_SLOWER)
+	assert.Equal(term.GetDelay(), 2*DEFAULT_DELAY, "Invalid delay.")
+	term.HandleKey(KEY_FASTER)
+	term.HandleKey(KEY_FASTER)
+	assert.Equal(term.GetDelay(), DEFAULT_DELAY/2, "Invalid delay.")
+
+	// The step key only steps when the game is paused.
+	term.HandleKey(KEY_PLAY)
+	term.HandleKey(KEY_STEP)
+	assert.Equal(term.game.GetCyclesNum(), uint(1), "The game has stepped while playing.")
+
+	for _, key := range []byte{KEY_QUIT, keyInterrupt} {
+		quit, err := term.HandleKey(key)
+		assert.Equal(quit, true, "The key does not quit.")
+		assert.Equal(err, nil, "There is an error.")
+	}
+
+	term.SetDelay(0)
+	assert.Equal(term.GetDelay(), minDelay, "Invalid minimum delay.")
+	term.SetDelay(time.Hour)
+	assert.Equal(term.GetDelay(), maxDelay, "Invalid maximum delay.")
+}
+
+// Test the function Run.
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	// Paused game stepped with the keyboard.
+	term, out := auxTerminal()
+	term.SetPlaying(false)
+	err := term.Run(strings.NewReader("nnnq"))
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(term.game.GetCyclesNum(), uint(3), "Invalid cycles.")
+	assert.Equal(strings.HasPrefix(out.String(), escHideCursor+escClear), true, "The cursor is not hidden.")
+	assert.Equal(strings.HasSuffix(out.String(), escShowCursor+"\r\n"), true, "The cursor is not shown.")
+
+	// Playing game until the end of the input.
+	term, _ = auxTerminal()
+	term.SetDelay(minDelay)
+	r, w := io.Pipe()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		w.Close()
+	}()
+
+	err = term.Run(r)
+	assert.Equal(err, nil, "There is an error.")
+	assert.Equal(term.game.GetCyclesNum() > 0, true, "The game has not run cycles.")
+}
+
+// Test the function readKeys ends when the keys are not read anymore.
+func TestReadKeysDone(t *testing.T) {
+	assert := assert.New(t)
+	keys, done := make(chan byte), make(chan struct{})
+	close(done)
+
+	// Without `done` it would wait forever sending the first key.
+	readKeys(strings.NewReader("nnq"), keys, done)
+	_, ok := <-keys
+	assert.Equal(ok, false, "The channel of the keys is not closed.")
+}
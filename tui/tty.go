@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Runs the command stty with the arguments `args` in the terminal `tty`. Returns its output.
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// Puts the terminal `tty` in raw mode, so the keys are read without waiting the end of line
+// and they are not written. Returns the function that restores the terminal.
+// It needs the command stty.
+func MakeRaw(tty *os.File) (func() error, error) {
+	state, err := stty(tty, "-g")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := stty(tty, "-icanon", "-echo", "-isig", "min", "1"); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		_, err := stty(tty, state)
+		return err
+	}, nil
+}
+
+// Returns the number of columns and rows of the terminal `tty`. It needs the command stty.
+func GetSize(tty *os.File) (int, int, error) {
+	out, err := stty(tty, "size")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var rows, cols int
+	if _, err := fmt.Sscanf(out, "%d %d", &rows, &cols); err != nil {
+		return 0, 0, err
+	}
+
+	return cols, rows, nil
+}